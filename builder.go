@@ -1,6 +1,9 @@
 package sqlingo
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
 func appendWhere(sb *strings.Builder, scope scope, where BooleanExpression) error {
 	if where == nil {
@@ -23,3 +26,18 @@ func appendWhere(sb *strings.Builder, scope scope, where BooleanExpression) erro
 	sb.WriteString(whereSql)
 	return nil
 }
+
+// appendRouteHint writes the routing hint of the statement as a leading comment, e.g. /* shard=users_3 */,
+// which is read by sharding proxies. The hint must not close the comment early.
+func appendRouteHint(sb *strings.Builder, hint string) error {
+	if hint == "" {
+		return nil
+	}
+	if strings.Contains(hint, "*/") {
+		return fmt.Errorf("route hint %q cannot contain */", hint)
+	}
+	sb.WriteString("/* ")
+	sb.WriteString(hint)
+	sb.WriteString(" */ ")
+	return nil
+}
@@ -19,3 +19,62 @@ func TestAppendWhere(t *testing.T) {
 	assertEqual(t, buildWhere(False()), " WHERE FALSE")
 	assertEqual(t, buildWhere(Raw("##")), " WHERE ##")
 }
+
+func TestRouteHint(t *testing.T) {
+	db := newMockDatabase()
+	if _, err := db.SelectFrom(Test).Where(Test.F1.Equals(1)).RouteHint("shard=users_3").FetchAll(); err != nil {
+		t.Error(err)
+	}
+	assertLastSql(t, "/* shard=users_3 */ SELECT * FROM `test` WHERE `f1` = 1")
+
+	// the hint of the outer statement is not repeated in the subquery
+	sql, err := db.SelectFrom(Test).Where(Test.F1.In(db.Select(Test.F1).From(Test))).RouteHint("shard=users_3").GetSQL()
+	if err != nil {
+		t.Error(err)
+	}
+	if strings.Count(sql, "shard=users_3") != 1 {
+		t.Error(sql)
+	}
+
+	// the hint goes to the head of the select wrapping the statement
+	oldColumnCount := sharedMockConn.columnCount
+	sharedMockConn.columnCount = 1
+	if _, err := db.SelectFrom(Test).GroupBy(Test.F2).RouteHint("shard=users_3").Count(); err != nil {
+		t.Error(err)
+	}
+	assertLastSql(t, "/* shard=users_3 */ SELECT COUNT(1) FROM (SELECT 1 FROM `test` GROUP BY `f2`) AS t")
+	if _, err := db.SelectFrom(Test).Where(Test.F1.Equals(1)).RouteHint("shard=users_3").Exists(); err != nil {
+		t.Error(err)
+	}
+	assertLastSql(t, "/* shard=users_3 */ SELECT EXISTS (SELECT * FROM `test` WHERE `f1` = 1)")
+	sharedMockConn.columnCount = oldColumnCount
+
+	if _, err := db.InsertInto(Test).Fields(Test.F1).Values(1).RouteHint("shard=users_3").Execute(); err != nil {
+		t.Error(err)
+	}
+	assertLastSql(t, "/* shard=users_3 */ INSERT INTO `test` (`f1`) VALUES (1)")
+
+	if _, err := db.Update(Test).Set(Test.F1, 2).Where(Test.F1.Equals(1)).RouteHint("shard=users_3").Execute(); err != nil {
+		t.Error(err)
+	}
+	assertLastSql(t, "/* shard=users_3 */ UPDATE `test` SET `f1` = 2 WHERE `f1` = 1")
+
+	if _, err := db.DeleteFrom(Test).Where(Test.F1.Equals(1)).RouteHint("shard=users_3").Execute(); err != nil {
+		t.Error(err)
+	}
+	assertLastSql(t, "/* shard=users_3 */ DELETE FROM `test` WHERE `f1` = 1")
+
+	// the caller info goes before the hint
+	db.EnableCallerInfo(true)
+	if _, err := db.DeleteFrom(Test).Where(Test.F1.Equals(1)).RouteHint("shard=users_3").Execute(); err != nil {
+		t.Error(err)
+	}
+	if !strings.HasPrefix(sharedMockConn.lastSql, "/* ") ||
+		!strings.HasSuffix(sharedMockConn.lastSql, "*/ /* shard=users_3 */ DELETE FROM `test` WHERE `f1` = 1") {
+		t.Error(sharedMockConn.lastSql)
+	}
+
+	if _, err := db.SelectFrom(Test).RouteHint("shard=1 */ DROP TABLE test; /*").GetSQL(); err == nil {
+		t.Error("should get error here")
+	}
+}
@@ -50,6 +50,20 @@ func command(name string, arg interface{}) expression {
 	}}
 }
 
+// Cast creates an expression of CAST(expr AS sqlType). The type is emitted verbatim as it is dialect-specific.
+func Cast(expr interface{}, sqlType string) UnknownExpression {
+	return expression{builder: func(scope scope) (string, error) {
+		sql, priority, err := getSQL(scope, expr)
+		if err != nil {
+			return "", err
+		}
+		if priority > 0 {
+			sql = "(" + sql + ")"
+		}
+		return "CAST(" + sql + " AS " + sqlType + ")", nil
+	}}
+}
+
 func commaFields(scope scope, fields []Field) (string, error) {
 	var sqlBuilder strings.Builder
 	sqlBuilder.Grow(128)
@@ -73,7 +87,7 @@ func commaExpressions(scope scope, expressions []Expression) (string, error) {
 		if i > 0 {
 			sqlBuilder.WriteString(", ")
 		}
-		itemSql, err := item.GetSQL(scope)
+		itemSql, _, err := getSQL(scope, item)
 		if err != nil {
 			return "", err
 		}
@@ -82,16 +96,33 @@ func commaExpressions(scope scope, expressions []Expression) (string, error) {
 	return sqlBuilder.String(), nil
 }
 
-func commaTables(scope scope, tables []Table) string {
+func commaTables(scope scope, tables []Table) (string, error) {
 	var sqlBuilder strings.Builder
 	sqlBuilder.Grow(32)
 	for i, table := range tables {
 		if i > 0 {
 			sqlBuilder.WriteString(", ")
 		}
-		sqlBuilder.WriteString(table.GetSQL(scope))
+		tableSql, err := getTableSQL(scope, table)
+		if err != nil {
+			return "", err
+		}
+		sqlBuilder.WriteString(tableSql)
+	}
+	return sqlBuilder.String(), nil
+}
+
+// subqueryTable is a table built from a subquery, whose error is reported by the enclosing statement.
+type subqueryTable interface {
+	getSQL(scope scope) (string, error)
+}
+
+// getTableSQL returns the SQL of the table in FROM or JOIN, with the error of building a derived table.
+func getTableSQL(scope scope, table Table) (string, error) {
+	if t, ok := table.(subqueryTable); ok {
+		return t.getSQL(scope)
 	}
-	return sqlBuilder.String()
+	return table.GetSQL(scope), nil
 }
 
 func commaValues(scope scope, values []interface{}) (string, error) {
@@ -130,7 +161,7 @@ func commaOrderBys(scope scope, orderBys []OrderBy) (string, error) {
 		if i > 0 {
 			sqlBuilder.WriteString(", ")
 		}
-		itemSql, err := item.GetSQL(scope)
+		itemSql, _, err := getSQL(scope, item)
 		if err != nil {
 			return "", err
 		}
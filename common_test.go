@@ -90,3 +90,16 @@ func TestCommon(t *testing.T) {
 		t.Error()
 	}
 }
+
+func TestCast(t *testing.T) {
+	a := expression{sql: "a"}
+	b := expression{sql: "b"}
+
+	assertValue(t, Cast(a, "DECIMAL(10,2)"), "CAST(a AS DECIMAL(10,2))")
+	assertValue(t, Cast("1", "SIGNED"), "CAST('1' AS SIGNED)")
+	assertValue(t, a.Add(b).Cast("SIGNED"), "CAST((a + b) AS SIGNED)")
+	assertValue(t, a.Cast("CHAR").Equals("1"), "CAST(a AS CHAR) = '1'")
+	assertError(t, Cast(expression{builder: func(scope scope) (string, error) {
+		return "", errors.New("error")
+	}}, "SIGNED"))
+}
@@ -0,0 +1,280 @@
+package sqlingo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// CTE is a reusable common table expression. It can be attached to any select with With
+// and referenced as a table in the select.
+type CTE interface {
+	Table
+	// Column creates a reference to a column of the CTE.
+	Column(name string) Field
+}
+
+type cteTable struct {
+	name  string
+	query toSelectFinal
+}
+
+// DefineCTE defines a named common table expression by the query, which can be attached to selects with With.
+func DefineCTE(name string, query toSelectFinal) CTE {
+	return cteTable{name: name, query: query}
+}
+
+func (t cteTable) GetName() string {
+	return t.name
+}
+
+func (t cteTable) GetSQL(scope scope) string {
+	return quoteIdentifier(t.name)[getDialect(scope)]
+}
+
+// GetFields returns the columns of the CTE, named after the fields or aliases in the select list of the query.
+func (t cteTable) GetFields() []Field {
+	s, ok := t.query.(selectStatus)
+	if !ok {
+		return nil
+	}
+	return getSelectColumns(t, s)
+}
+
+func (t cteTable) Column(name string) Field {
+	return newField(t, name, columnUnknown)
+}
+
+type cte struct {
+	previous *cte
+	name     string
+	query    toSelectFinal
+}
+
+type withClause struct {
+	recursive bool
+	lastCTE   *cte
+}
+
+type cteBuilder interface {
+	With(name string, query toSelectFinal) cteBuilder
+	WithRecursive(name string, query toSelectFinal) cteBuilder
+	Select(fields ...interface{}) selectWithFields
+	SelectDistinct(fields ...interface{}) selectWithFields
+	SelectFrom(tables ...Table) selectWithTables
+}
+
+type cteStatus struct {
+	database *database
+	with     withClause
+	err      error
+}
+
+// With starts a select with a common table expression (CTE) defined by the query.
+// The CTE can be referenced in the main query as NewTable(name).
+func With(name string, query toSelectFinal) cteBuilder {
+	db, err := getQueryDatabase(query)
+	return cteStatus{database: db, err: err}.With(name, query)
+}
+
+// WithRecursive is like With, but emits WITH RECURSIVE so that the query can reference the CTE itself.
+func WithRecursive(name string, query toSelectFinal) cteBuilder {
+	db, err := getQueryDatabase(query)
+	return cteStatus{database: db, err: err}.WithRecursive(name, query)
+}
+
+func getQueryDatabase(query toSelectFinal) (*database, error) {
+	if s, ok := query.(selectStatus); ok && s.base.scope.Database != nil {
+		return s.base.scope.Database, nil
+	}
+	// the main query is built without a database to execute on, and fails with the error
+	return &database{}, errors.New("the query of a CTE should be a select statement of a database")
+}
+
+func (s cteStatus) With(name string, query toSelectFinal) cteBuilder {
+	s.with.lastCTE = &cte{previous: s.with.lastCTE, name: name, query: query}
+	return s
+}
+
+func (s cteStatus) WithRecursive(name string, query toSelectFinal) cteBuilder {
+	s.with.recursive = true
+	return s.With(name, query)
+}
+
+// attach attaches the CTEs to the main query, which fails with the error of the CTEs if any.
+func (s cteStatus) attach(status selectStatus) selectStatus {
+	status.with = &s.with
+	if s.err != nil {
+		status.base.where = errorExpression(s.err)
+	}
+	return status
+}
+
+func (s cteStatus) Select(fields ...interface{}) selectWithFields {
+	return s.attach(s.database.Select(fields...).(selectStatus))
+}
+
+func (s cteStatus) SelectDistinct(fields ...interface{}) selectWithFields {
+	return s.attach(s.database.SelectDistinct(fields...).(selectStatus))
+}
+
+func (s cteStatus) SelectFrom(tables ...Table) selectWithTables {
+	return s.attach(s.database.SelectFrom(tables...).(selectStatus))
+}
+
+// With attaches the CTEs to the select. The CTEs already attached to the select are kept.
+func (s selectStatus) With(ctes ...CTE) toSelectFinal {
+	with := withClause{}
+	if s.with != nil {
+		with = *s.with
+	}
+	for _, c := range ctes {
+		t, ok := c.(cteTable)
+		if !ok {
+			return s.Where(errorExpression(fmt.Errorf("unsupported CTE %T", c))).(selectStatus)
+		}
+		with.lastCTE = &cte{previous: with.lastCTE, name: t.name, query: t.query}
+	}
+	s.with = &with
+	return s
+}
+
+func (w withClause) buildWith(sb *strings.Builder, dialect dialect, args *argList) error {
+	var ctes []*cte
+	for c := w.lastCTE; c != nil; c = c.previous {
+		ctes = append(ctes, c)
+	}
+	sb.WriteString("WITH ")
+	if w.recursive && dialect != dialectMSSQL {
+		// SQL Server detects recursive CTEs without the keyword
+		sb.WriteString("RECURSIVE ")
+	}
+	for i := len(ctes) - 1; i >= 0; i-- {
+		querySql, err := getStatementSQL(args, ctes[i].query)
+		if err != nil {
+			return err
+		}
+		sb.WriteString(quoteIdentifier(ctes[i].name)[dialect])
+		sb.WriteString(" AS (")
+		sb.WriteString(querySql)
+		sb.WriteString(")")
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+	}
+	sb.WriteString(" ")
+	return nil
+}
+
+// TraverseTree selects the rows of a tree stored as an adjacency list, i.e. linked by parentField to idField,
+// from the row of startID down to maxDepth levels of descendants, by a recursive CTE named table_tree.
+// Besides the fields of the table, the rows have a depth column counting from 0 at the start row.
+// As the depth is limited, a cycle in the data cannot make the traversal endless.
+func (d *database) TraverseTree(table Table, idField Field, parentField Field, startID interface{}, maxDepth int) selectWithTables {
+	return d.traverseTree(table, idField, parentField, startID, maxDepth, false)
+}
+
+// TraverseTreeWithCycleGuard is like TraverseTree, but a row already on the path from the start row is not visited
+// again, so that a cycle in the data does not repeat rows up to maxDepth. The rows have an additional path column
+// of the ids from the start row, which is an array on PostgreSQL and a comma-separated string elsewhere.
+func (d *database) TraverseTreeWithCycleGuard(table Table, idField Field, parentField Field, startID interface{}, maxDepth int) selectWithTables {
+	return d.traverseTree(table, idField, parentField, startID, maxDepth, true)
+}
+
+func (d *database) traverseTree(table Table, idField Field, parentField Field, startID interface{}, maxDepth int, cycleGuard bool) selectWithTables {
+	name := table.GetName() + "_tree"
+	tree := NewTable(name)
+	depth := NewNumberField(tree, "depth")
+	idName, err := getFieldName(idField)
+	if err != nil {
+		return d.SelectFrom(tree).Where(errorExpression(err)).(selectStatus)
+	}
+	treeID := NewNumberField(tree, idName)
+
+	var fields []interface{}
+	for _, field := range table.GetFields() {
+		fields = append(fields, field)
+	}
+	anchorFields := append(append([]interface{}{}, fields...), staticExpression("0", 0, false).As("depth"))
+	recursiveFields := append(append([]interface{}{}, fields...), depth.Add(1))
+	recursiveCondition := depth.LessThan(maxDepth)
+	if cycleGuard {
+		path := NewStringField(tree, "path")
+		anchorFields = append(anchorFields, startTreePath(idField).As("path"))
+		recursiveFields = append(recursiveFields, extendTreePath(path, idField))
+		recursiveCondition = recursiveCondition.And(isNotOnTreePath(path, idField))
+	}
+
+	query := d.Select(anchorFields...).From(table).Where(idField.Equals(startID)).
+		UnionAllSelect(recursiveFields...).From(table).Join(tree).On(parentField.Equals(treeID)).
+		Where(recursiveCondition)
+	return WithRecursive(name, query).SelectFrom(tree)
+}
+
+// startTreePath creates the path of the start row of TraverseTreeWithCycleGuard. Elsewhere than on PostgreSQL,
+// the ids are enclosed by commas, e.g. ",1,2,", and the type is widened as the type of a recursive CTE column
+// is taken from the anchor member.
+func startTreePath(id Field) expression {
+	return expression{builder: func(scope scope) (string, error) {
+		idSql, err := id.GetSQL(scope)
+		if err != nil {
+			return "", err
+		}
+		switch getDialect(scope) {
+		case dialectPostgres:
+			return "ARRAY[" + idSql + "]", nil
+		case dialectSqlite3:
+			return "',' || " + idSql + " || ','", nil
+		case dialectMSSQL:
+			return "CAST(CONCAT(',', " + idSql + ", ',') AS VARCHAR(MAX))", nil
+		default:
+			return "CAST(CONCAT(',', " + idSql + ", ',') AS CHAR(4000))", nil
+		}
+	}}
+}
+
+// extendTreePath creates the path of a descendant row from the path of its parent.
+func extendTreePath(path Field, id Field) expression {
+	return expression{builder: func(scope scope) (string, error) {
+		pathSql, err := path.GetSQL(scope)
+		if err != nil {
+			return "", err
+		}
+		idSql, err := id.GetSQL(scope)
+		if err != nil {
+			return "", err
+		}
+		switch getDialect(scope) {
+		case dialectPostgres:
+			return pathSql + " || " + idSql, nil
+		case dialectSqlite3:
+			return pathSql + " || " + idSql + " || ','", nil
+		case dialectMSSQL:
+			return "CAST(CONCAT(" + pathSql + ", " + idSql + ", ',') AS VARCHAR(MAX))", nil
+		default:
+			return "CONCAT(" + pathSql + ", " + idSql + ", ',')", nil
+		}
+	}}
+}
+
+// isNotOnTreePath creates the condition that the row is not on the path yet.
+func isNotOnTreePath(path Field, id Field) expression {
+	return expression{builder: func(scope scope) (string, error) {
+		pathSql, err := path.GetSQL(scope)
+		if err != nil {
+			return "", err
+		}
+		idSql, err := id.GetSQL(scope)
+		if err != nil {
+			return "", err
+		}
+		switch getDialect(scope) {
+		case dialectPostgres:
+			return idSql + " <> ALL(" + pathSql + ")", nil
+		case dialectSqlite3:
+			return pathSql + " NOT LIKE '%,' || " + idSql + " || ',%'", nil
+		default:
+			return pathSql + " NOT LIKE CONCAT('%,', " + idSql + ", ',%')", nil
+		}
+	}, priority: 11, isBool: true}
+}
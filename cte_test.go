@@ -0,0 +1,121 @@
+package sqlingo
+
+import "testing"
+
+func TestWith(t *testing.T) {
+	db := newMockDatabase()
+	recent := NewTable("recent")
+	recentField1 := NewNumberField(recent, "field1")
+
+	_, _ = With("recent", db.Select(field1).From(table1).Where(field2.GreaterThan(10))).
+		Select(recentField1).From(recent).Where(recentField1.LessThan(5)).
+		FetchAll()
+	assertLastSql(t, "WITH `recent` AS (SELECT `field1` FROM `table1` WHERE `field2` > 10) "+
+		"SELECT `field1` FROM `recent` WHERE `field1` < 5")
+
+	_, _ = With("a", db.Select(field1).From(table1)).
+		With("b", db.Select(field3).From(table2)).
+		SelectFrom(NewTable("a"), NewTable("b")).
+		FetchAll()
+	assertLastSql(t, "WITH `a` AS (SELECT `field1` FROM `table1`), `b` AS (SELECT `field3` FROM `table2`) "+
+		"SELECT *, * FROM `a`, `b`")
+
+	nums := NewTable("nums")
+	n := NewNumberField(nums, "n")
+	query := db.Select(field1).From(table1).UnionAllSelect(n.Add(1)).From(nums).Where(n.LessThan(10))
+	_, _ = WithRecursive("nums", query).SelectDistinct(n).From(nums).FetchAll()
+	assertLastSql(t, "WITH RECURSIVE `nums` AS (SELECT `field1` FROM `table1` UNION ALL SELECT `n` + 1 FROM `nums` WHERE `n` < 10) "+
+		"SELECT DISTINCT `n` FROM `nums`")
+
+	db.(*database).dialect = dialectMSSQL
+	_, _ = WithRecursive("nums", query).SelectFrom(nums).FetchAll()
+	assertLastSql(t, "WITH [nums] AS (SELECT [field1] FROM [table1] UNION ALL SELECT [n] + 1 FROM [nums] WHERE [n] < 10) "+
+		"SELECT * FROM [nums]")
+}
+
+func TestDefineCTE(t *testing.T) {
+	db := newMockDatabase()
+	recent := DefineCTE("recent", db.Select(field1, field2.Add(1).As("next")).From(table1).Where(field2.GreaterThan(10)))
+	recentField1 := recent.Column("field1")
+
+	_, _ = db.SelectFrom(recent).Where(recentField1.LessThan(5)).With(recent).FetchAll()
+	assertLastSql(t, "WITH `recent` AS (SELECT `field1`, `field2` + 1 AS next FROM `table1` WHERE `field2` > 10) "+
+		"SELECT * FROM `recent` WHERE `field1` < 5")
+
+	_, _ = db.Select(recent).From(recent).With(recent).FetchAll()
+	assertLastSql(t, "WITH `recent` AS (SELECT `field1`, `field2` + 1 AS next FROM `table1` WHERE `field2` > 10) "+
+		"SELECT `field1`, `next` FROM `recent`")
+
+	_, _ = db.Select(field1, recentField1).From(table1).Join(recent).On(field1.Equals(recentField1)).With(recent).FetchAll()
+	assertLastSql(t, "WITH `recent` AS (SELECT `field1`, `field2` + 1 AS next FROM `table1` WHERE `field2` > 10) "+
+		"SELECT `table1`.`field1`, `recent`.`field1` FROM `table1` JOIN `recent` ON `table1`.`field1` = `recent`.`field1`")
+
+	other := DefineCTE("other", db.Select(field3).From(table2))
+	_, _ = With("a", db.Select(field4).From(table3)).SelectFrom(recent, other).With(recent, other).FetchAll()
+	assertLastSql(t, "WITH `a` AS (SELECT `field4` FROM `table3`), "+
+		"`recent` AS (SELECT `field1`, `field2` + 1 AS next FROM `table1` WHERE `field2` > 10), `other` AS (SELECT `field3` FROM `table2`) "+
+		"SELECT *, * FROM `recent`, `other`")
+
+	derived := db.Select(field1).From(table1).As("d")
+	if _, err := db.SelectFrom(derived).With(derived).FetchAll(); err == nil {
+		t.Error("should fail with a derived table as a CTE")
+	}
+}
+
+func TestWithNonSelectQuery(t *testing.T) {
+	db := newMockDatabase()
+	query := struct{ toSelectFinal }{db.Select(field1).From(table1)}
+	if _, err := With("a", query).SelectFrom(NewTable("a")).FetchAll(); err == nil {
+		t.Error("should fail with a query that is not a select statement")
+	}
+	if _, err := WithRecursive("a", query).Select(1).FetchAll(); err == nil {
+		t.Error("should fail with a query that is not a select statement")
+	}
+}
+
+func TestTraverseTree(t *testing.T) {
+	db := newMockDatabase()
+	_, _ = db.TraverseTree(Test, Test.F1, Test.F2, 1, 3).Where(NewNumberField(NewTable("test_tree"), "depth").GreaterThan(0)).FetchAll()
+	assertLastSql(t, "WITH RECURSIVE `test_tree` AS ("+
+		"SELECT `f1`, `f2`, 0 AS depth FROM `test` WHERE `f1` = 1 "+
+		"UNION ALL SELECT `test`.`f1`, `test`.`f2`, `test_tree`.`depth` + 1 FROM `test` JOIN `test_tree` ON `test`.`f2` = `test_tree`.`f1` "+
+		"WHERE `test_tree`.`depth` < 3) "+
+		"SELECT * FROM `test_tree` WHERE `depth` > 0")
+
+	db.(*database).dialect = dialectPostgres
+	_, _ = db.TraverseTree(Table1, field1, field2, 5, 10).FetchAll()
+	assertLastSql(t, `WITH RECURSIVE "table1_tree" AS (`+
+		`SELECT "field1", "field2", 0 AS depth FROM "table1" WHERE "field1" = 5 `+
+		`UNION ALL SELECT "table1"."field1", "table1"."field2", "table1_tree"."depth" + 1 FROM "table1" JOIN "table1_tree" ON "table1"."field2" = "table1_tree"."field1" `+
+		`WHERE "table1_tree"."depth" < 10) `+
+		`SELECT * FROM "table1_tree"`)
+}
+
+func TestTraverseTreeWithCycleGuard(t *testing.T) {
+	db := newMockDatabase()
+	_, _ = db.TraverseTreeWithCycleGuard(Test, Test.F1, Test.F2, 1, 3).FetchAll()
+	assertLastSql(t, "WITH RECURSIVE `test_tree` AS ("+
+		"SELECT `f1`, `f2`, 0 AS depth, CAST(CONCAT(',', `f1`, ',') AS CHAR(4000)) AS path FROM `test` WHERE `f1` = 1 "+
+		"UNION ALL SELECT `test`.`f1`, `test`.`f2`, `test_tree`.`depth` + 1, CONCAT(`test_tree`.`path`, `test`.`f1`, ',') "+
+		"FROM `test` JOIN `test_tree` ON `test`.`f2` = `test_tree`.`f1` "+
+		"WHERE `test_tree`.`depth` < 3 AND `test_tree`.`path` NOT LIKE CONCAT('%,', `test`.`f1`, ',%')) "+
+		"SELECT * FROM `test_tree`")
+
+	db.(*database).dialect = dialectPostgres
+	_, _ = db.TraverseTreeWithCycleGuard(Test, Test.F1, Test.F2, 1, 3).FetchAll()
+	assertLastSql(t, `WITH RECURSIVE "test_tree" AS (`+
+		`SELECT "f1", "f2", 0 AS depth, ARRAY["f1"] AS path FROM "test" WHERE "f1" = 1 `+
+		`UNION ALL SELECT "test"."f1", "test"."f2", "test_tree"."depth" + 1, "test_tree"."path" || "test"."f1" `+
+		`FROM "test" JOIN "test_tree" ON "test"."f2" = "test_tree"."f1" `+
+		`WHERE "test_tree"."depth" < 3 AND "test"."f1" <> ALL("test_tree"."path")) `+
+		`SELECT * FROM "test_tree"`)
+
+	db.(*database).dialect = dialectSqlite3
+	_, _ = db.TraverseTreeWithCycleGuard(Test, Test.F1, Test.F2, 1, 3).FetchAll()
+	assertLastSql(t, `WITH RECURSIVE "test_tree" AS (`+
+		`SELECT "f1", "f2", 0 AS depth, ',' || "f1" || ',' AS path FROM "test" WHERE "f1" = 1 `+
+		`UNION ALL SELECT "test"."f1", "test"."f2", "test_tree"."depth" + 1, "test_tree"."path" || "test"."f1" || ',' `+
+		`FROM "test" JOIN "test_tree" ON "test"."f2" = "test_tree"."f1" `+
+		`WHERE "test_tree"."depth" < 3 AND "test_tree"."path" NOT LIKE '%,' || "test"."f1" || ',%') `+
+		`SELECT * FROM "test_tree"`)
+}
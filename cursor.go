@@ -24,7 +24,25 @@ type Cursor interface {
 }
 
 type cursor struct {
-	rows *sql.Rows
+	rows            *sql.Rows
+	nullableColumns map[int]bool
+}
+
+// withNullableColumns marks the columns of the fields wrapped by Nullable.
+func withNullableColumns(c Cursor, fields []Field) Cursor {
+	cur, ok := c.(cursor)
+	if !ok {
+		return c
+	}
+	for i, field := range fields {
+		if _, ok := field.(nullableExpression); ok {
+			if cur.nullableColumns == nil {
+				cur.nullableColumns = make(map[int]bool)
+			}
+			cur.nullableColumns[i] = true
+		}
+	}
+	return cur
 }
 
 func (c cursor) Next() bool {
@@ -179,6 +197,15 @@ func (c cursor) Scan(dest ...interface{}) error {
 		}
 	}
 
+	for i := range scans {
+		if c.nullableColumns[i] && i < len(values) && values[i] == nil {
+			// leave the zero value and discard the NULL
+			target := reflect.ValueOf(scans[i]).Elem()
+			target.Set(reflect.Zero(target.Type()))
+			scans[i] = new(interface{})
+		}
+	}
+
 	pbs := make(map[int]*bool)
 	ppbs := make(map[int]**bool)
 	pts := make(map[int]*time.Time)
@@ -18,11 +18,13 @@ type mockConn struct {
 	prepareError error
 	columnCount  int
 	rowCount     int
+	execResult   driver.Result // the result of the executed statements, driver.ResultNoRows if nil
 }
 
 type mockStmt struct {
 	columnCount int
 	rowCount    int
+	execResult  driver.Result
 }
 
 type mockRows struct {
@@ -78,10 +80,13 @@ func (m mockStmt) Close() error {
 }
 
 func (m mockStmt) NumInput() int {
-	return 0
+	return -1
 }
 
 func (m mockStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if m.execResult != nil {
+		return m.execResult, nil
+	}
 	return driver.ResultNoRows, nil
 }
 
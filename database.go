@@ -26,6 +26,8 @@ type Database interface {
 	GetDB() *sql.DB
 	// BeginTx starts a transaction and executes the function f.
 	BeginTx(ctx context.Context, opts *sql.TxOptions, f func(tx Transaction) error) error
+	// Transaction executes fn in a transaction, which is committed if fn succeeds and rolled back otherwise.
+	Transaction(ctx context.Context, fn func(tx Database) error) error
 	// Query executes a query and returns the cursor
 	Query(sql string) (Cursor, error)
 	// QueryContext executes a query with context and returns the cursor
@@ -45,6 +47,27 @@ type Database interface {
 	EnableCallerInfo(enableCallerInfo bool)
 	// SetInterceptor sets an interceptor function
 	SetInterceptor(interceptor InterceptorFunc)
+	// SetQueryLogger sets the structured query logger.
+	SetQueryLogger(logger Logger)
+	// EnableCartesianProductCheck enable or disable the check of implicit cross joins in SELECT statements.
+	EnableCartesianProductCheck(enable bool)
+	// EnableDuplicateColumnAliases enable or disable aliasing the same-named columns of different tables in SELECT statements.
+	EnableDuplicateColumnAliases(enable bool)
+	// EnableAliasInlining enable or disable inlining the expressions of aliases used as operands.
+	EnableAliasInlining(enable bool)
+	// EnableParameterizedQuery enable or disable sending the values as bound arguments instead of inlining them.
+	EnableParameterizedQuery(enable bool)
+	// SetInlineThreshold sets the maximum number of IN values that are inlined in parameterized queries.
+	SetInlineThreshold(n int)
+	// SetDialect sets the dialect of the database, overriding the one inferred from the driver name.
+	SetDialect(dialect Dialect)
+	// SetMaxSQLLength sets the maximum length in bytes of the SQL built from statements.
+	SetMaxSQLLength(n int)
+	// SetTimeFormat sets the layout of the time literals in the SQL.
+	SetTimeFormat(layout string)
+	// SetMaxFetchRows sets the limit added to selects without one in FetchAll, FetchSeq and FetchCursor,
+	// for catching unbounded selects in development.
+	SetMaxFetchRows(n int)
 
 	// Select initiates a SELECT statement
 	Select(fields ...interface{}) selectWithFields
@@ -52,14 +75,32 @@ type Database interface {
 	SelectDistinct(fields ...interface{}) selectWithFields
 	// SelectFrom initiates a SELECT * FROM statement
 	SelectFrom(tables ...Table) selectWithTables
+	// TraverseTree initiates a SELECT of the descendants of a row in a tree by a recursive CTE
+	TraverseTree(table Table, idField Field, parentField Field, startID interface{}, maxDepth int) selectWithTables
+	// TraverseTreeWithCycleGuard is like TraverseTree, but does not visit a row on the path from the start row again
+	TraverseTreeWithCycleGuard(table Table, idField Field, parentField Field, startID interface{}, maxDepth int) selectWithTables
 	// InsertInto initiates a INSERT INTO statement
 	InsertInto(table Table) insertWithTable
 	// ReplaceInto initiates a REPLACE INTO statement
 	ReplaceInto(table Table) insertWithTable
+	// InsertFixture initiates a INSERT INTO statement of a row with placeholder values for test fixtures
+	InsertFixture(model Model, overrides map[Field]interface{}) insertWithRows
 	// Update initiates a UPDATE statement
 	Update(table Table) updateWithSet
+	// Sync initiates an upsert of the models, reconciling the table with them
+	Sync(table Table, models interface{}, keyFields ...Field) syncWithModels
 	// DeleteFrom initiates a DELETE FROM statement
 	DeleteFrom(table Table) deleteWithTable
+	// DeleteInBatches initiates a purge of the matching rows by repeated DELETE ... LIMIT statements
+	DeleteInBatches(table Table, condition BooleanExpression, batchSize int) deleteInBatches
+	// CreateTable initiates a CREATE TABLE statement from a model
+	CreateTable(model Model) createTableWithModel
+	// CreateTempTable initiates a CREATE TEMPORARY TABLE ... AS SELECT statement
+	CreateTempTable(name string, query toSelectFinal) createTempTableWithQuery
+	// DropTable initiates a DROP TABLE statement
+	DropTable(table Table) dropTableWithTable
+	// AlterTable initiates an ALTER TABLE statement
+	AlterTable(table Table) alterTableWithTable
 }
 
 type txOrDB interface {
@@ -80,6 +121,16 @@ type database struct {
 	retryPolicy      func(error) bool
 	enableCallerInfo bool
 	interceptor      InterceptorFunc
+
+	cartesianProductCheck  bool
+	duplicateColumnAliases bool
+	aliasInlining          bool
+	queryLogger            Logger
+	parameterized          bool
+	inlineThreshold        int
+	maxSQLLength           int
+	timeFormat             string
+	maxFetchRows           int
 }
 
 type LoggerFunc func(sql string, duration time.Duration, isTx bool, retry bool)
@@ -156,6 +207,87 @@ func (d *database) EnableCallerInfo(enableCallerInfo bool) {
 	d.enableCallerInfo = enableCallerInfo
 }
 
+// EnableCartesianProductCheck makes a SELECT fail before execution if it lists several tables in FROM
+// and some of them are not referenced by the WHERE clause, which usually indicates an accidental
+// Cartesian product. It is a debugging aid and is disabled by default.
+func (d *database) EnableCartesianProductCheck(enable bool) {
+	d.cartesianProductCheck = enable
+}
+
+// EnableDuplicateColumnAliases makes a SELECT over several tables alias the columns of different tables
+// with the same name as table_column, so that the columns of the result set are named uniquely.
+// The columns of a CTE or a derived table built from such a SELECT are named after the aliases as well.
+// It is disabled by default.
+func (d *database) EnableDuplicateColumnAliases(enable bool) {
+	d.duplicateColumnAliases = enable
+}
+
+// EnableAliasInlining makes an alias created by As render as its underlying expression when it is used
+// as an operand or in ORDER BY, e.g. Select(total, field.Add(total)) with total := price.Mul(qty).As("total"),
+// since referencing a select alias in the same SELECT is not portable. When it is disabled, which is the default,
+// the alias is referenced by its name.
+func (d *database) EnableAliasInlining(enable bool) {
+	d.aliasInlining = enable
+}
+
+// EnableParameterizedQuery makes the statement builders execute with placeholders and bound arguments,
+// e.g. `id` = ? on MySQL, $1 on PostgreSQL and @p1 on SQL Server, instead of inlining the quoted values.
+// It is disabled by default. Interceptors see the SQL with placeholders.
+func (d *database) EnableParameterizedQuery(enable bool) {
+	d.parameterized = enable
+}
+
+// SetInlineThreshold makes the IN lists of at most n values inlined even if parameterized queries are enabled,
+// as small lists are cheap to inline and may be planned better. The default is 0, which binds all the values.
+func (d *database) SetInlineThreshold(n int) {
+	d.inlineThreshold = n
+}
+
+// SetDialect sets the dialect of the database, for drivers whose names the dialect cannot be inferred from.
+// The values out of the known dialects are treated as DialectUnknown.
+func (d *database) SetDialect(dialect Dialect) {
+	d.dialect = dialect.dialect()
+}
+
+// SetMaxSQLLength makes building a statement fail if its SQL is longer than n bytes, which surfaces runaway
+// query construction, e.g. an unbounded IN list, before it reaches the database. The default is 0, which means
+// unlimited.
+func (d *database) SetMaxSQLLength(n int) {
+	d.maxSQLLength = n
+}
+
+// SetTimeFormat sets the layout of time.Time values inlined into the SQL, e.g. "2006-01-02 15:04:05.000"
+// for a DATETIME(3) column, so that the fraction is not truncated or rounded by the database.
+// An empty layout restores the default "2006-01-02 15:04:05.000000". Bound arguments are not affected.
+func (d *database) SetTimeFormat(layout string) {
+	d.timeFormat = layout
+}
+
+// SetMaxFetchRows makes FetchAll, FetchSeq and FetchCursor add LIMIT n+1 to a select without a limit, and fail
+// if more than n rows are returned, which catches a missing condition or limit during development before the whole
+// table is loaded into memory. FetchInBatches is already bounded by its batch size.
+// The default is 0, which means off, as it is meant for debugging rather than production.
+func (d *database) SetMaxFetchRows(n int) {
+	d.maxFetchRows = n
+}
+
+// finishSQL passes through the result of building a statement with its spacing normalized, or returns an error
+// if the SQL is longer than the limit set by SetMaxSQLLength.
+func (d *database) finishSQL(sqlString string, err error) (string, error) {
+	if err != nil {
+		return sqlString, err
+	}
+	dialect := dialectUnknown
+	if d != nil {
+		dialect = d.dialect
+	}
+	sqlString = normalizeSpaces(sqlString, dialect)
+	if d != nil && d.maxSQLLength > 0 && len(sqlString) > d.maxSQLLength {
+		return "", fmt.Errorf("SQL length %d exceeds the maximum length %d", len(sqlString), d.maxSQLLength)
+	}
+	return sqlString, nil
+}
+
 func (d *database) SetInterceptor(interceptor InterceptorFunc) {
 	d.interceptor = interceptor
 }
@@ -175,6 +307,17 @@ func Open(driverName string, dataSourceName string) (db Database, err error) {
 	return
 }
 
+// OpenWithDialect opens a database like Open, but with the dialect given explicitly
+// instead of inferred from the driver name, e.g. for drivers of unknown names or proxies.
+func OpenWithDialect(driverName string, dataSourceName string, dialect Dialect) (db Database, err error) {
+	db, err = Open(driverName, dataSourceName)
+	if err != nil {
+		return
+	}
+	db.SetDialect(dialect)
+	return
+}
+
 // Use an existing *sql.DB handle
 func Use(driverName string, sqlDB *sql.DB) Database {
 	return &database{
@@ -199,10 +342,14 @@ func (d database) Query(sqlString string) (Cursor, error) {
 }
 
 func (d database) QueryContext(ctx context.Context, sqlString string) (Cursor, error) {
+	return d.queryContext(ctx, sqlString, nil)
+}
+
+func (d database) queryContext(ctx context.Context, sqlString string, args []interface{}) (Cursor, error) {
 	isRetry := false
 	for {
 		sqlStringWithCallerInfo := getCallerInfo(d, isRetry) + sqlString
-		rows, err := d.queryContextOnce(ctx, sqlStringWithCallerInfo, isRetry)
+		rows, err := d.queryContextOnce(ctx, sqlStringWithCallerInfo, args, isRetry)
 		if err != nil {
 			isRetry = d.tx == nil && d.retryPolicy != nil && d.retryPolicy(err)
 			if isRetry {
@@ -214,7 +361,7 @@ func (d database) QueryContext(ctx context.Context, sqlString string) (Cursor, e
 	}
 }
 
-func (d database) queryContextOnce(ctx context.Context, sqlString string, retry bool) (*sql.Rows, error) {
+func (d database) queryContextOnce(ctx context.Context, sqlString string, args []interface{}, retry bool) (_ *sql.Rows, err error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
@@ -224,16 +371,16 @@ func (d database) queryContextOnce(ctx context.Context, sqlString string, retry
 		if d.logger != nil {
 			d.logger(sqlString, endTime.Sub(startTime), false, retry)
 		}
+		d.logQuery(ctx, sqlString, args, endTime.Sub(startTime), err)
 	}()
 
 	interceptor := d.interceptor
 	var rows *sql.Rows
 	invoker := func(ctx context.Context, sql string) (err error) {
-		rows, err = d.getTxOrDB().QueryContext(ctx, sql)
+		rows, err = d.getTxOrDB().QueryContext(ctx, sql, args...)
 		return
 	}
 
-	var err error
 	if interceptor == nil {
 		err = invoker(ctx, sqlString)
 	} else {
@@ -250,8 +397,13 @@ func (d database) Execute(sqlString string) (sql.Result, error) {
 	return d.ExecuteContext(context.Background(), sqlString)
 }
 
-// ExecuteContext todo Is there need retry?
+// ExecuteContext executes the statement with the context, which is passed through to database/sql.
+// Unlike queries, statements are never retried as they may not be idempotent.
 func (d database) ExecuteContext(ctx context.Context, sqlString string) (sql.Result, error) {
+	return d.executeContext(ctx, sqlString, nil)
+}
+
+func (d database) executeContext(ctx context.Context, sqlString string, args []interface{}) (_ sql.Result, err error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
@@ -262,14 +414,14 @@ func (d database) ExecuteContext(ctx context.Context, sqlString string) (sql.Res
 		if d.logger != nil {
 			d.logger(sqlStringWithCallerInfo, endTime.Sub(startTime), false, false)
 		}
+		d.logQuery(ctx, sqlStringWithCallerInfo, args, endTime.Sub(startTime), err)
 	}()
 
 	var result sql.Result
 	invoker := func(ctx context.Context, sql string) (err error) {
-		result, err = d.getTxOrDB().ExecContext(ctx, sql)
+		result, err = d.getTxOrDB().ExecContext(ctx, sql, args...)
 		return
 	}
-	var err error
 	if d.interceptor == nil {
 		err = invoker(ctx, sqlStringWithCallerInfo)
 	} else {
@@ -17,6 +17,7 @@ func (m *mockConn) Prepare(query string) (driver.Stmt, error) {
 	return &mockStmt{
 		columnCount: m.columnCount,
 		rowCount:    m.rowCount,
+		execResult:  m.execResult,
 	}, nil
 }
 
@@ -114,3 +115,56 @@ func TestDatabaseRetry(t *testing.T) {
 	}
 	sharedMockConn.prepareError = nil
 }
+
+func TestMaxSQLLength(t *testing.T) {
+	db := newMockDatabase()
+	db.SetMaxSQLLength(40)
+
+	if _, err := db.SelectFrom(Test).Where(Test.F1.Equals(1)).GetSQL(); err != nil {
+		t.Error(err)
+	}
+	_, err := db.SelectFrom(Test).Where(Test.F1.In(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)).FetchAll()
+	if err == nil || err.Error() != "SQL length 66 exceeds the maximum length 40" {
+		t.Error(err)
+	}
+	if _, err := db.Update(Test).Set(Test.F2, "a long long long long value").Where(Test.F1.Equals(1)).Execute(); err == nil {
+		t.Error("should fail for long SQL")
+	}
+	if _, _, err := db.InsertInto(Test).Values(1, "a").Values(2, "b").Values(3, "c").GetSQLWithArgs(); err == nil {
+		t.Error("should fail for long SQL")
+	}
+
+	// the DDL is checked as well, and a temporary table by its whole statement rather than its query
+	if _, err := db.AlterTable(Test).AddColumn("description", "VARCHAR(255) NOT NULL").Execute(); err == nil {
+		t.Error("should fail for long SQL")
+	}
+	if _, err := db.CreateTempTable("active", db.SelectFrom(table1)).GetSQL(); err == nil {
+		t.Error("should fail for long SQL")
+	}
+	if _, err := db.DropTable(Test).IfExists().GetSQL(); err != nil {
+		t.Error(err)
+	}
+
+	db.SetMaxSQLLength(0)
+	if _, err := db.Update(Test).Set(Test.F2, "a long long long long value").Where(Test.F1.Equals(1)).Execute(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSetTimeFormat(t *testing.T) {
+	db := newMockDatabase()
+	tm := time.Date(2024, 1, 2, 3, 4, 5, 678912345, time.UTC)
+
+	_, _ = db.Update(Test).Set(Test.F2, tm).Where(Test.F1.Equals(1)).Execute()
+	assertLastSql(t, "UPDATE `test` SET `f2` = '2024-01-02 03:04:05.678912' WHERE `f1` = 1")
+
+	db.SetTimeFormat("2006-01-02 15:04:05.000")
+	_, _ = db.Update(Test).Set(Test.F2, &tm).Where(Test.F1.Equals(1)).Execute()
+	assertLastSql(t, "UPDATE `test` SET `f2` = '2024-01-02 03:04:05.678' WHERE `f1` = 1")
+	_, _ = db.SelectFrom(Test).Where(Test.F2.LessThan(tm)).FetchAll()
+	assertLastSql(t, "SELECT * FROM `test` WHERE `f2` < '2024-01-02 03:04:05.678'")
+
+	db.SetTimeFormat("")
+	_, _ = db.SelectFrom(Test).Where(Test.F2.LessThan(tm)).FetchAll()
+	assertLastSql(t, "SELECT * FROM `test` WHERE `f2` < '2024-01-02 03:04:05.678912'")
+}
@@ -0,0 +1,181 @@
+package sqlingo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// dateUnits maps the supported interval units to their SQLite modifiers and multipliers,
+// as SQLite has no week unit.
+var dateUnits = map[string]struct {
+	sqliteModifier   string
+	sqliteMultiplier int
+}{
+	"SECOND": {"seconds", 1},
+	"MINUTE": {"minutes", 1},
+	"HOUR":   {"hours", 1},
+	"DAY":    {"days", 1},
+	"WEEK":   {"days", 7},
+	"MONTH":  {"months", 1},
+	"YEAR":   {"years", 1},
+}
+
+// DateAdd creates an expression of the date plus value units of interval, which is one of SECOND, MINUTE, HOUR,
+// DAY, WEEK, MONTH and YEAR in any case, e.g. DATE_ADD(date, INTERVAL 3 DAY) on MySQL.
+func (e expression) DateAdd(interval string, value interface{}) DateExpression {
+	return e.dateArithmetic(interval, value, false)
+}
+
+// DateSub creates an expression of the date minus value units of interval. See DateAdd for the units.
+func (e expression) DateSub(interval string, value interface{}) DateExpression {
+	return e.dateArithmetic(interval, value, true)
+}
+
+func (e expression) dateArithmetic(interval string, value interface{}, subtract bool) expression {
+	return expression{builder: func(scope scope) (string, error) {
+		unit := strings.ToUpper(interval)
+		sqliteUnit, ok := dateUnits[unit]
+		if !ok {
+			return "", fmt.Errorf("unknown interval unit %s", interval)
+		}
+		dateSql, err := e.GetSQL(scope)
+		if err != nil {
+			return "", err
+		}
+		valueSql, valuePriority, err := getSQL(scope, value)
+		if err != nil {
+			return "", err
+		}
+		operand := expression{sql: valueSql, priority: valuePriority}
+
+		switch getDialect(scope) {
+		case dialectPostgres:
+			operator := " + "
+			if subtract {
+				operator = " - "
+			}
+			if e.priority > 7 {
+				dateSql = "(" + dateSql + ")"
+			}
+			return dateSql + operator + "(" + concatUnit(valueSql, valuePriority, strings.ToLower(unit)) + ")::interval", nil
+		case dialectSqlite3:
+			factor := sqliteUnit.sqliteMultiplier
+			if subtract {
+				factor = -factor
+			}
+			amountSql, amountPriority := valueSql, valuePriority
+			if factor != 1 {
+				if amountSql, err = operand.Mul(factor).GetSQL(scope); err != nil {
+					return "", err
+				}
+				amountPriority = 6
+			}
+			return "DATETIME(" + dateSql + ", " + concatUnit(amountSql, amountPriority, sqliteUnit.sqliteModifier) + ")", nil
+		case dialectMSSQL:
+			amountSql := valueSql
+			if subtract {
+				if amountSql, err = operand.Negate().GetSQL(scope); err != nil {
+					return "", err
+				}
+			}
+			return "DATEADD(" + unit + ", " + amountSql + ", " + dateSql + ")", nil
+		default:
+			name := "DATE_ADD("
+			if subtract {
+				name = "DATE_SUB("
+			}
+			return name + dateSql + ", INTERVAL " + valueSql + " " + unit + ")", nil
+		}
+	}, priority: 7} // the lowest priority of the forms
+}
+
+// concatUnit concatenates the amount and the unit into an interval string, e.g. 3 || ' day'.
+func concatUnit(amountSql string, amountPriority priority, unit string) string {
+	if amountPriority > 0 {
+		amountSql = "(" + amountSql + ")"
+	}
+	return amountSql + " || " + quoteString(" "+unit)
+}
+
+// DateDiff creates an expression of the number of days from date b to date a, i.e. DATEDIFF(a, b) on MySQL.
+// The times of the day are ignored.
+func DateDiff(a interface{}, b interface{}) NumberExpression {
+	return expression{builder: func(scope scope) (string, error) {
+		aSql, _, err := getSQL(scope, a)
+		if err != nil {
+			return "", err
+		}
+		bSql, _, err := getSQL(scope, b)
+		if err != nil {
+			return "", err
+		}
+		switch getDialect(scope) {
+		case dialectPostgres:
+			return "(CAST(" + aSql + " AS DATE) - CAST(" + bSql + " AS DATE))", nil
+		case dialectSqlite3:
+			return "CAST(JULIANDAY(DATE(" + aSql + ")) - JULIANDAY(DATE(" + bSql + ")) AS INTEGER)", nil
+		case dialectMSSQL:
+			return "DATEDIFF(DAY, " + bSql + ", " + aSql + ")", nil
+		default:
+			return "DATEDIFF(" + aSql + ", " + bSql + ")", nil
+		}
+	}}
+}
+
+// Year creates an expression of the year of the date, e.g. YEAR(date) on MySQL and EXTRACT(YEAR FROM date)
+// on PostgreSQL.
+func (e expression) Year() NumberExpression {
+	return e.datePart("YEAR", "%Y")
+}
+
+// Month creates an expression of the month of the date, from 1 to 12.
+func (e expression) Month() NumberExpression {
+	return e.datePart("MONTH", "%m")
+}
+
+// Day creates an expression of the day of the month of the date.
+func (e expression) Day() NumberExpression {
+	return e.datePart("DAY", "%d")
+}
+
+// Hour creates an expression of the hour of the time.
+func (e expression) Hour() NumberExpression {
+	return e.datePart("HOUR", "%H")
+}
+
+// Minute creates an expression of the minute of the time.
+func (e expression) Minute() NumberExpression {
+	return e.datePart("MINUTE", "%M")
+}
+
+// Second creates an expression of the second of the time, which has the fraction on PostgreSQL.
+func (e expression) Second() NumberExpression {
+	return e.datePart("SECOND", "%S")
+}
+
+// datePart extracts the part of the date by the function of the same name on MySQL, EXTRACT on PostgreSQL,
+// DATEPART on SQL Server and STRFTIME with the format on SQLite.
+func (e expression) datePart(part string, sqliteFormat string) expression {
+	prefixes := dialectArray{
+		dialectUnknown:  part + "(",
+		dialectMySQL:    part + "(",
+		dialectSqlite3:  "CAST(STRFTIME('" + sqliteFormat + "', ",
+		dialectPostgres: "EXTRACT(" + part + " FROM ",
+		dialectMSSQL:    "DATEPART(" + part + ", ",
+	}
+	suffixes := dialectArray{
+		dialectUnknown:  ")",
+		dialectMySQL:    ")",
+		dialectSqlite3:  ") AS INTEGER)",
+		dialectPostgres: ")",
+		dialectMSSQL:    ")",
+	}
+	return expression{builder: func(scope scope) (string, error) {
+		sql, err := e.GetSQL(scope)
+		if err != nil {
+			return "", err
+		}
+		dialect := getDialect(scope)
+		return prefixes[dialect] + sql + suffixes[dialect], nil
+	}}
+}
@@ -0,0 +1,62 @@
+package sqlingo
+
+import "testing"
+
+func TestDateArithmetic(t *testing.T) {
+	d := expression{sql: "d"}
+
+	assertValue(t, d.DateAdd("day", 3), "DATE_ADD(d, INTERVAL 3 DAY)")
+	assertValue(t, d.DateSub("MONTH", field1), "DATE_SUB(d, INTERVAL `table1`.`field1` MONTH)")
+	assertValue(t, d.DateAdd("hour", 1).GreaterThan(d), "DATE_ADD(d, INTERVAL 1 HOUR) > d")
+	assertError(t, d.DateAdd("fortnight", 1))
+
+	assertDialectValue(t, dialectPostgres, d.DateAdd("day", 3), "d + (3 || ' day')::interval")
+	assertDialectValue(t, dialectPostgres, d.DateSub("week", 2), "d - (2 || ' week')::interval")
+	assertDialectValue(t, dialectPostgres, d.DateAdd("day", 3).DateSub("hour", 1),
+		"d + (3 || ' day')::interval - (1 || ' hour')::interval")
+	assertDialectValue(t, dialectPostgres, d.DateAdd("day", d.Add(1)), "d + ((d + 1) || ' day')::interval")
+	assertDialectValue(t, dialectPostgres, d.DateAdd("day", 3).Equals(d), "d + (3 || ' day')::interval = d")
+
+	assertDialectValue(t, dialectSqlite3, d.DateAdd("day", 3), "DATETIME(d, 3 || ' days')")
+	assertDialectValue(t, dialectSqlite3, d.DateSub("day", 3), "DATETIME(d, (3 * -1) || ' days')")
+	assertDialectValue(t, dialectSqlite3, d.DateAdd("week", 2), "DATETIME(d, (2 * 7) || ' days')")
+
+	assertDialectValue(t, dialectMSSQL, d.DateAdd("day", 3), "DATEADD(DAY, 3, d)")
+	assertDialectValue(t, dialectMSSQL, d.DateSub("year", 1), "DATEADD(YEAR, -1, d)")
+}
+
+func TestDateDiff(t *testing.T) {
+	a := expression{sql: "a"}
+	b := expression{sql: "b"}
+
+	assertValue(t, DateDiff(a, b), "DATEDIFF(a, b)")
+	assertValue(t, DateDiff(a, b).GreaterThan(7), "DATEDIFF(a, b) > 7")
+	assertDialectValue(t, dialectPostgres, DateDiff(a, "2024-01-01"), "(CAST(a AS DATE) - CAST('2024-01-01' AS DATE))")
+	assertDialectValue(t, dialectSqlite3, DateDiff(a, b), "CAST(JULIANDAY(DATE(a)) - JULIANDAY(DATE(b)) AS INTEGER)")
+	assertDialectValue(t, dialectMSSQL, DateDiff(a, b), "DATEDIFF(DAY, b, a)")
+
+	db := newMockDatabase()
+	_, _ = db.SelectFrom(table1).Where(DateDiff(field1, field2).LessThan(30)).FetchAll()
+	assertLastSql(t, "SELECT * FROM `table1` WHERE DATEDIFF(`field1`, `field2`) < 30")
+}
+
+func TestDateParts(t *testing.T) {
+	d := expression{sql: "d"}
+
+	assertValue(t, d.Year(), "YEAR(d)")
+	assertValue(t, d.Month().Equals(12), "MONTH(d) = 12")
+	assertValue(t, d.DateAdd("day", 1).Day(), "DAY(DATE_ADD(d, INTERVAL 1 DAY))")
+	assertDialectValue(t, dialectPostgres, d.Year(), "EXTRACT(YEAR FROM d)")
+	assertDialectValue(t, dialectPostgres, d.Second(), "EXTRACT(SECOND FROM d)")
+	assertDialectValue(t, dialectSqlite3, d.Hour(), "CAST(STRFTIME('%H', d) AS INTEGER)")
+	assertDialectValue(t, dialectSqlite3, d.Minute(), "CAST(STRFTIME('%M', d) AS INTEGER)")
+	assertDialectValue(t, dialectMSSQL, d.Day(), "DATEPART(DAY, d)")
+
+	db := newMockDatabase()
+	createdAt := NewDateField(table1, "created_at")
+	_, _ = db.Select(createdAt.Year(), Count(1)).From(table1).GroupBy(createdAt.Year()).FetchAll()
+	assertLastSql(t, "SELECT YEAR(`created_at`), COUNT(1) FROM `table1` GROUP BY YEAR(`created_at`)")
+	db.(*database).dialect = dialectPostgres
+	_, _ = db.Select(createdAt.Year(), Count(1)).From(table1).GroupBy(createdAt.Year()).FetchAll()
+	assertLastSql(t, `SELECT EXTRACT(YEAR FROM "created_at"), COUNT(1) FROM "table1" GROUP BY EXTRACT(YEAR FROM "created_at")`)
+}
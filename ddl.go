@@ -0,0 +1,481 @@
+package sqlingo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type createTableStatus struct {
+	scope       scope
+	model       Model
+	ifNotExists bool
+	ctx         context.Context
+}
+
+type createTableWithModel interface {
+	toCreateTableWithContext
+	toCreateTableFinal
+	IfNotExists() createTableWithIfNotExists
+}
+
+type createTableWithIfNotExists interface {
+	toCreateTableWithContext
+	toCreateTableFinal
+}
+
+type toCreateTableWithContext interface {
+	WithContext(ctx context.Context) toCreateTableFinal
+}
+
+type toCreateTableFinal interface {
+	GetSQL() (string, error)
+	Execute() (sql.Result, error)
+}
+
+// CreateTable initiates a CREATE TABLE statement for the table of the model.
+// The column types are derived from the Go types of the model values on a best-effort basis,
+// and pointer values are considered nullable. The primary key of the table, if any, is declared as well.
+func (d *database) CreateTable(model Model) createTableWithModel {
+	return createTableStatus{scope: scope{Database: d, Tables: []Table{model.GetTable()}}, model: model}
+}
+
+func (s createTableStatus) IfNotExists() createTableWithIfNotExists {
+	s.ifNotExists = true
+	return s
+}
+
+func (s createTableStatus) GetSQL() (string, error) {
+	return s.scope.Database.finishSQL(s.buildSQL())
+}
+
+func (s createTableStatus) buildSQL() (string, error) {
+	table := s.scope.Tables[0]
+	fields := table.GetFields()
+	values := s.model.GetValues()
+	if len(fields) != len(values) {
+		return "", errors.New("number of fields and values of the model mismatch")
+	}
+
+	dialect := getDialect(s.scope)
+	if s.ifNotExists {
+		if err := checkDialect(s.scope, "CREATE TABLE IF NOT EXISTS", dialectMySQL, dialectSqlite3, dialectPostgres); err != nil {
+			return "", err
+		}
+	}
+
+	var sb strings.Builder
+	sb.Grow(128)
+	sb.WriteString("CREATE TABLE ")
+	if s.ifNotExists {
+		sb.WriteString("IF NOT EXISTS ")
+	}
+	sb.WriteString(table.GetSQL(s.scope))
+	sb.WriteString(" (")
+	var keyFields []Field
+	if primaryKeyTable, ok := table.(PrimaryKeyTable); ok {
+		keyFields = primaryKeyTable.GetPrimaryKeyFields()
+	}
+	for i, field := range fields {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		fieldSql, err := field.GetSQL(s.scope)
+		if err != nil {
+			return "", err
+		}
+		typ := reflect.TypeOf(values[i])
+		if typ == nil {
+			return "", fmt.Errorf("cannot determine the type of %s", fieldSql)
+		}
+		nullable := typ.Kind() == reflect.Ptr
+		if nullable {
+			typ = typ.Elem()
+		}
+		for _, keyField := range keyFields {
+			if isSameField(field, keyField) {
+				// the columns of the primary key cannot be NULL
+				nullable = false
+			}
+		}
+		columnType, err := getColumnType(dialect, typ)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(fieldSql)
+		sb.WriteString(" ")
+		sb.WriteString(columnType)
+		if nullable {
+			sb.WriteString(" NULL")
+		} else {
+			sb.WriteString(" NOT NULL")
+		}
+	}
+	if len(keyFields) > 0 {
+		sb.WriteString(", PRIMARY KEY (")
+		for i, keyField := range keyFields {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			keyName, err := getFieldName(keyField)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(quoteIdentifier(keyName)[dialect])
+		}
+		sb.WriteString(")")
+	}
+	sb.WriteString(")")
+	return sb.String(), nil
+}
+
+func (s createTableStatus) WithContext(ctx context.Context) toCreateTableFinal {
+	s.ctx = ctx
+	return s
+}
+
+func (s createTableStatus) Execute() (sql.Result, error) {
+	sqlString, err := s.GetSQL()
+	if err != nil {
+		return nil, err
+	}
+	return s.scope.Database.ExecuteContext(s.ctx, sqlString)
+}
+
+var wellKnownBinaryType = reflect.TypeOf(WellKnownBinary{})
+
+// getColumnType maps a Go type of a generated model to the column type of the dialect.
+func getColumnType(dialect dialect, typ reflect.Type) (string, error) {
+	var types dialectArray
+	switch {
+	case typ == timeType:
+		types = dialectArray{"TIMESTAMP", "DATETIME", "DATETIME", "TIMESTAMP", "DATETIME2"}
+	case typ == wellKnownBinaryType:
+		types = dialectArray{"GEOMETRY", "GEOMETRY", "BLOB", "GEOMETRY", "GEOMETRY"}
+	case typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8:
+		types = dialectArray{"BLOB", "BLOB", "BLOB", "BYTEA", "VARBINARY(MAX)"}
+	default:
+		switch typ.Kind() {
+		case reflect.Bool:
+			types = dialectArray{"BOOLEAN", "BOOLEAN", "INTEGER", "BOOLEAN", "BIT"}
+		case reflect.Int8:
+			types = dialectArray{"SMALLINT", "TINYINT", "INTEGER", "SMALLINT", "SMALLINT"}
+		case reflect.Int16:
+			types = dialectArray{"SMALLINT", "SMALLINT", "INTEGER", "SMALLINT", "SMALLINT"}
+		case reflect.Int32:
+			types = dialectArray{"INTEGER", "INT", "INTEGER", "INTEGER", "INT"}
+		case reflect.Int, reflect.Int64:
+			types = dialectArray{"BIGINT", "BIGINT", "INTEGER", "BIGINT", "BIGINT"}
+		case reflect.Uint8:
+			types = dialectArray{"SMALLINT", "TINYINT UNSIGNED", "INTEGER", "SMALLINT", "TINYINT"}
+		case reflect.Uint16:
+			types = dialectArray{"INTEGER", "SMALLINT UNSIGNED", "INTEGER", "INTEGER", "INT"}
+		case reflect.Uint32:
+			types = dialectArray{"BIGINT", "INT UNSIGNED", "INTEGER", "BIGINT", "BIGINT"}
+		case reflect.Uint, reflect.Uint64:
+			types = dialectArray{"NUMERIC(20)", "BIGINT UNSIGNED", "INTEGER", "NUMERIC(20)", "DECIMAL(20)"}
+		case reflect.Float32, reflect.Float64:
+			types = dialectArray{"DOUBLE PRECISION", "DOUBLE", "REAL", "DOUBLE PRECISION", "FLOAT"}
+		case reflect.String:
+			types = dialectArray{"VARCHAR(255)", "VARCHAR(255)", "TEXT", "TEXT", "NVARCHAR(255)"}
+		default:
+			return "", fmt.Errorf("unsupported column type %s", typ.String())
+		}
+	}
+	return types[dialect], nil
+}
+
+type dropTableStatus struct {
+	scope    scope
+	ifExists bool
+	cascade  bool
+	ctx      context.Context
+}
+
+type dropTableWithTable interface {
+	toDropTableWithContext
+	toDropTableFinal
+	IfExists() dropTableWithIfExists
+	Cascade() dropTableWithCascade
+}
+
+type dropTableWithIfExists interface {
+	toDropTableWithContext
+	toDropTableFinal
+	Cascade() dropTableWithCascade
+}
+
+type dropTableWithCascade interface {
+	toDropTableWithContext
+	toDropTableFinal
+}
+
+type toDropTableWithContext interface {
+	WithContext(ctx context.Context) toDropTableFinal
+}
+
+type toDropTableFinal interface {
+	GetSQL() (string, error)
+	Execute() (sql.Result, error)
+}
+
+// DropTable initiates a DROP TABLE statement.
+func (d *database) DropTable(table Table) dropTableWithTable {
+	return dropTableStatus{scope: scope{Database: d, Tables: []Table{table}}}
+}
+
+func (s dropTableStatus) IfExists() dropTableWithIfExists {
+	s.ifExists = true
+	return s
+}
+
+// Cascade also drops the objects depending on the table. It is not supported by SQLite and SQL Server.
+func (s dropTableStatus) Cascade() dropTableWithCascade {
+	s.cascade = true
+	return s
+}
+
+func (s dropTableStatus) GetSQL() (string, error) {
+	return s.scope.Database.finishSQL(s.buildSQL())
+}
+
+func (s dropTableStatus) buildSQL() (string, error) {
+	if s.cascade {
+		if err := checkDialect(s.scope, "DROP TABLE CASCADE", dialectMySQL, dialectPostgres); err != nil {
+			return "", err
+		}
+	}
+
+	var sb strings.Builder
+	sb.Grow(64)
+	sb.WriteString("DROP TABLE ")
+	if s.ifExists {
+		sb.WriteString("IF EXISTS ")
+	}
+	sb.WriteString(s.scope.Tables[0].GetSQL(s.scope))
+	if s.cascade {
+		sb.WriteString(" CASCADE")
+	}
+	return sb.String(), nil
+}
+
+func (s dropTableStatus) WithContext(ctx context.Context) toDropTableFinal {
+	s.ctx = ctx
+	return s
+}
+
+func (s dropTableStatus) Execute() (sql.Result, error) {
+	sqlString, err := s.GetSQL()
+	if err != nil {
+		return nil, err
+	}
+	return s.scope.Database.ExecuteContext(s.ctx, sqlString)
+}
+
+type alterTableAction struct {
+	previous   *alterTableAction
+	drop       bool
+	name       string
+	definition string
+}
+
+type alterTableStatus struct {
+	scope      scope
+	lastAction *alterTableAction
+	ctx        context.Context
+}
+
+type alterTableWithTable interface {
+	AddColumn(name string, definition string) alterTableWithAction
+	DropColumn(name string) alterTableWithAction
+}
+
+type alterTableWithAction interface {
+	toAlterTableWithContext
+	toAlterTableFinal
+	AddColumn(name string, definition string) alterTableWithAction
+	DropColumn(name string) alterTableWithAction
+}
+
+type toAlterTableWithContext interface {
+	WithContext(ctx context.Context) toAlterTableFinal
+}
+
+type toAlterTableFinal interface {
+	GetSQL() (string, error)
+	Execute() (sql.Result, error)
+}
+
+// AlterTable initiates an ALTER TABLE statement.
+func (d *database) AlterTable(table Table) alterTableWithTable {
+	return alterTableStatus{scope: scope{Database: d, Tables: []Table{table}}}
+}
+
+// AddColumn adds a column. The definition, e.g. "INT NOT NULL DEFAULT 0", is emitted verbatim.
+func (s alterTableStatus) AddColumn(name string, definition string) alterTableWithAction {
+	s.lastAction = &alterTableAction{previous: s.lastAction, name: name, definition: definition}
+	return s
+}
+
+func (s alterTableStatus) DropColumn(name string) alterTableWithAction {
+	s.lastAction = &alterTableAction{previous: s.lastAction, drop: true, name: name}
+	return s
+}
+
+func (s alterTableStatus) GetSQL() (string, error) {
+	return s.scope.Database.finishSQL(s.buildSQL())
+}
+
+func (s alterTableStatus) buildSQL() (string, error) {
+	var actions []*alterTableAction
+	for action := s.lastAction; action != nil; action = action.previous {
+		actions = append(actions, action)
+	}
+
+	dialect := getDialect(s.scope)
+	switch dialect {
+	case dialectSqlite3:
+		if len(actions) > 1 {
+			return "", errors.New("SQLite supports only one action per ALTER TABLE")
+		}
+	case dialectMSSQL:
+		for _, action := range actions {
+			if action.drop != actions[0].drop {
+				return "", errors.New("SQL Server cannot add and drop columns in one ALTER TABLE")
+			}
+		}
+	}
+
+	var sb strings.Builder
+	sb.Grow(64)
+	sb.WriteString("ALTER TABLE ")
+	sb.WriteString(s.scope.Tables[0].GetSQL(s.scope))
+	for i := len(actions) - 1; i >= 0; i-- {
+		action := actions[i]
+		if i < len(actions)-1 {
+			sb.WriteString(",")
+		}
+		switch {
+		case dialect == dialectMSSQL && i < len(actions)-1:
+			// SQL Server lists all the columns after a single ADD or DROP COLUMN
+			sb.WriteString(" ")
+		case action.drop:
+			sb.WriteString(" DROP COLUMN ")
+		case dialect == dialectMSSQL:
+			// SQL Server does not accept the COLUMN keyword when adding columns
+			sb.WriteString(" ADD ")
+		default:
+			sb.WriteString(" ADD COLUMN ")
+		}
+		sb.WriteString(quoteIdentifier(action.name)[dialect])
+		if !action.drop {
+			sb.WriteString(" ")
+			sb.WriteString(action.definition)
+		}
+	}
+	return sb.String(), nil
+}
+
+func (s alterTableStatus) WithContext(ctx context.Context) toAlterTableFinal {
+	s.ctx = ctx
+	return s
+}
+
+func (s alterTableStatus) Execute() (sql.Result, error) {
+	sqlString, err := s.GetSQL()
+	if err != nil {
+		return nil, err
+	}
+	return s.scope.Database.ExecuteContext(s.ctx, sqlString)
+}
+
+// TempTable is a temporary table created from a query, which can be referenced as a table in the later statements
+// on the same connection.
+type TempTable interface {
+	Table
+	// Column creates a reference to a column of the temporary table.
+	Column(name string) Field
+}
+
+// tempTable shares the columns of a CTE, but not its type, as it cannot be attached to a select by With.
+type tempTable struct {
+	query cteTable
+}
+
+func (t tempTable) GetName() string {
+	return t.query.GetName()
+}
+
+func (t tempTable) GetSQL(scope scope) string {
+	return t.query.GetSQL(scope)
+}
+
+func (t tempTable) GetFields() []Field {
+	return t.query.GetFields()
+}
+
+func (t tempTable) Column(name string) Field {
+	return t.query.Column(name)
+}
+
+type createTempTableStatus struct {
+	scope scope
+	table tempTable
+	ctx   context.Context
+}
+
+type createTempTableWithQuery interface {
+	toCreateTempTableFinal
+	WithContext(ctx context.Context) toCreateTempTableFinal
+}
+
+type toCreateTempTableFinal interface {
+	GetSQL() (string, error)
+	Execute() (sql.Result, error)
+	Table() TempTable
+}
+
+// CreateTempTable initiates a CREATE TEMPORARY TABLE name AS query statement. The table, which has the columns
+// of the select list of the query, is referenced by Table. As a temporary table is only visible to the connection
+// creating it, the statements using it should be executed in the same transaction.
+// It is not supported by SQL Server, which creates temporary tables by SELECT INTO.
+func (d *database) CreateTempTable(name string, query toSelectFinal) createTempTableWithQuery {
+	t := tempTable{query: cteTable{name: name, query: query}}
+	return createTempTableStatus{scope: scope{Database: d, Tables: []Table{t}}, table: t}
+}
+
+// Table returns the reference to the temporary table.
+func (s createTempTableStatus) Table() TempTable {
+	return s.table
+}
+
+func (s createTempTableStatus) GetSQL() (string, error) {
+	return s.scope.Database.finishSQL(s.buildSQL())
+}
+
+func (s createTempTableStatus) buildSQL() (string, error) {
+	if err := checkDialect(s.scope, "CREATE TEMPORARY TABLE", dialectMySQL, dialectSqlite3, dialectPostgres); err != nil {
+		return "", err
+	}
+	querySql, err := s.table.query.query.GetSQL()
+	if err != nil {
+		return "", err
+	}
+	return "CREATE TEMPORARY TABLE " + s.table.GetSQL(s.scope) + " AS " + querySql, nil
+}
+
+func (s createTempTableStatus) WithContext(ctx context.Context) toCreateTempTableFinal {
+	s.ctx = ctx
+	return s
+}
+
+func (s createTempTableStatus) Execute() (sql.Result, error) {
+	sqlString, err := s.GetSQL()
+	if err != nil {
+		return nil, err
+	}
+	return s.scope.Database.ExecuteContext(s.ctx, sqlString)
+}
@@ -0,0 +1,122 @@
+package sqlingo
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type nullableTestModel struct {
+	F1 *int64
+	F2 time.Time
+}
+
+func (m nullableTestModel) GetTable() Table {
+	return Test
+}
+
+func (m nullableTestModel) GetValues() []interface{} {
+	return []interface{}{m.F1, m.F2}
+}
+
+// table1Model is a model of a table without a primary key.
+type table1Model struct {
+	Field1 *int64
+	Field2 int32
+}
+
+func (m table1Model) GetTable() Table {
+	return Table1
+}
+
+func (m table1Model) GetValues() []interface{} {
+	return []interface{}{m.Field1, m.Field2}
+}
+
+func TestCreateTable(t *testing.T) {
+	db := newMockDatabase()
+
+	_, _ = db.CreateTable(&TestModel{}).Execute()
+	assertLastSql(t, "CREATE TABLE `test` (`f1` BIGINT NOT NULL, `f2` VARCHAR(255) NOT NULL, PRIMARY KEY (`f1`))")
+
+	_, _ = db.CreateTable(nullableTestModel{}).IfNotExists().WithContext(context.Background()).Execute()
+	assertLastSql(t, "CREATE TABLE IF NOT EXISTS `test` (`f1` BIGINT NOT NULL, `f2` DATETIME NOT NULL, PRIMARY KEY (`f1`))")
+
+	_, _ = db.CreateTable(table1Model{}).Execute()
+	assertLastSql(t, "CREATE TABLE `table1` (`field1` BIGINT NULL, `field2` INT NOT NULL)")
+
+	db.(*database).dialect = dialectPostgres
+	_, _ = db.CreateTable(nullableTestModel{}).Execute()
+	assertLastSql(t, `CREATE TABLE "test" ("f1" BIGINT NOT NULL, "f2" TIMESTAMP NOT NULL, PRIMARY KEY ("f1"))`)
+
+	db.(*database).dialect = dialectMSSQL
+	if _, err := db.CreateTable(&TestModel{}).IfNotExists().Execute(); err == nil {
+		t.Error("should fail on SQL Server")
+	}
+}
+
+func TestDropTable(t *testing.T) {
+	db := newMockDatabase()
+
+	_, _ = db.DropTable(table1).Execute()
+	assertLastSql(t, "DROP TABLE `table1`")
+
+	_, _ = db.DropTable(table1).IfExists().Cascade().WithContext(context.Background()).Execute()
+	assertLastSql(t, "DROP TABLE IF EXISTS `table1` CASCADE")
+
+	db.(*database).dialect = dialectSqlite3
+	if _, err := db.DropTable(table1).Cascade().Execute(); err == nil {
+		t.Error("should fail on SQLite")
+	}
+}
+
+func TestAlterTable(t *testing.T) {
+	db := newMockDatabase()
+
+	_, _ = db.AlterTable(table1).AddColumn("field3", "INT NOT NULL DEFAULT 0").DropColumn("field2").Execute()
+	assertLastSql(t, "ALTER TABLE `table1` ADD COLUMN `field3` INT NOT NULL DEFAULT 0, DROP COLUMN `field2`")
+
+	db.(*database).dialect = dialectMSSQL
+	_, _ = db.AlterTable(table1).AddColumn("a", "INT").AddColumn("b", "INT").Execute()
+	assertLastSql(t, "ALTER TABLE [table1] ADD [a] INT, [b] INT")
+	if _, err := db.AlterTable(table1).AddColumn("a", "INT").DropColumn("b").Execute(); err == nil {
+		t.Error("should fail on SQL Server")
+	}
+
+	db.(*database).dialect = dialectSqlite3
+	_, _ = db.AlterTable(table1).DropColumn("field2").WithContext(context.Background()).Execute()
+	assertLastSql(t, `ALTER TABLE "table1" DROP COLUMN "field2"`)
+	if _, err := db.AlterTable(table1).DropColumn("a").DropColumn("b").Execute(); err == nil {
+		t.Error("should fail on SQLite")
+	}
+}
+
+func TestCreateTempTable(t *testing.T) {
+	db := newMockDatabase()
+
+	err := db.BeginTx(context.Background(), nil, func(tx Transaction) error {
+		create := tx.CreateTempTable("active", tx.Select(field1, field2.Add(1).As("next")).From(table1).Where(field2.GreaterThan(10)))
+		if _, err := create.Execute(); err != nil {
+			return err
+		}
+		assertLastSql(t, "CREATE TEMPORARY TABLE `active` AS SELECT `field1`, `field2` + 1 AS next FROM `table1` WHERE `field2` > 10")
+
+		active := create.Table()
+		if fields := active.GetFields(); len(fields) != 2 {
+			t.Error(fields)
+		}
+		_, err := tx.Select(field1).From(table1).Join(active).On(active.Column("field1").Equals(field1)).
+			Where(active.Column("next").LessThan(20)).FetchAll()
+		assertLastSql(t, "SELECT `table1`.`field1` FROM `table1` JOIN `active` ON `active`.`field1` = `table1`.`field1` "+
+			"WHERE `active`.`next` < 20")
+		return err
+	})
+	if err != nil {
+		t.Error(err)
+	}
+
+	db.(*database).dialect = dialectMSSQL
+	if _, err := db.CreateTempTable("active", db.SelectFrom(table1)).WithContext(context.Background()).Execute(); err == nil {
+		t.Error("should fail on SQL Server")
+	}
+}
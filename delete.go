@@ -3,25 +3,32 @@ package sqlingo
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"strconv"
 	"strings"
 )
 
 type deleteStatus struct {
-	scope    scope
-	where    BooleanExpression
-	orderBys []OrderBy
-	limit    *int
-	ctx      context.Context
+	scope     scope
+	where     BooleanExpression
+	orderBys  []OrderBy
+	limit     *int
+	all       bool
+	returning []Field
+	routeHint string
+	ctx       context.Context
 }
 
 type deleteWithTable interface {
 	Where(conditions ...BooleanExpression) deleteWithWhere
+	WhereRaw(sql string, args ...interface{}) deleteWithWhere
+	DeleteAll() deleteWithWhere
 }
 
 type deleteWithWhere interface {
 	toDeleteWithContext
 	toDeleteFinal
+	Returning(fields ...Field) toReturningWithContext
 	OrderBy(orderBys ...OrderBy) deleteWithOrder
 	Limit(limit int) deleteWithLimit
 }
@@ -29,12 +36,14 @@ type deleteWithWhere interface {
 type deleteWithOrder interface {
 	toDeleteWithContext
 	toDeleteFinal
+	Returning(fields ...Field) toReturningWithContext
 	Limit(limit int) deleteWithLimit
 }
 
 type deleteWithLimit interface {
 	toDeleteWithContext
 	toDeleteFinal
+	Returning(fields ...Field) toReturningWithContext
 }
 
 type toDeleteWithContext interface {
@@ -42,7 +51,9 @@ type toDeleteWithContext interface {
 }
 
 type toDeleteFinal interface {
+	RouteHint(hint string) toDeleteFinal
 	GetSQL() (string, error)
+	GetSQLWithArgs() (string, []interface{}, error)
 	Execute() (result sql.Result, err error)
 }
 
@@ -55,6 +66,19 @@ func (s deleteStatus) Where(conditions ...BooleanExpression) deleteWithWhere {
 	return s
 }
 
+// DeleteAll acknowledges deleting all the rows of the table. Without it, a DELETE whose condition
+// is always true fails instead of wiping the table.
+func (s deleteStatus) DeleteAll() deleteWithWhere {
+	s.where = nil
+	s.all = true
+	return s
+}
+
+// WhereRaw sets the condition in raw SQL, where the ? placeholders are replaced by the quoted args.
+func (s deleteStatus) WhereRaw(sql string, args ...interface{}) deleteWithWhere {
+	return s.Where(Raw(sql, args...))
+}
+
 func (s deleteStatus) OrderBy(orderBys ...OrderBy) deleteWithOrder {
 	s.orderBys = orderBys
 	return s
@@ -65,9 +89,33 @@ func (s deleteStatus) Limit(limit int) deleteWithLimit {
 	return s
 }
 
+// RouteHint prepends the hint as a comment to the statement for a sharding proxy, e.g. /* shard=users_3 */.
+func (s deleteStatus) RouteHint(hint string) toDeleteFinal {
+	s.routeHint = hint
+	return s
+}
+
 func (s deleteStatus) GetSQL() (string, error) {
+	return s.scope.Database.finishSQL(s.buildSQL(nil))
+}
+
+// GetSQLWithArgs returns the SQL with placeholders and the values bound to them.
+func (s deleteStatus) GetSQLWithArgs() (string, []interface{}, error) {
+	args := &argList{}
+	sqlString, err := s.scope.Database.finishSQL(s.buildSQL(args))
+	return sqlString, args.values, err
+}
+
+func (s deleteStatus) buildSQL(args *argList) (string, error) {
+	s.scope.args = args
+	if e, ok := s.where.(expression); !s.all && (s.where == nil || ok && e.isTrue) {
+		return "", errors.New("DELETE without condition, use DeleteAll() to delete all rows")
+	}
 	var sb strings.Builder
 	sb.Grow(128)
+	if err := appendRouteHint(&sb, s.routeHint); err != nil {
+		return "", err
+	}
 
 	sb.WriteString("DELETE FROM ")
 	sb.WriteString(s.scope.Tables[0].GetSQL(s.scope))
@@ -90,18 +138,92 @@ func (s deleteStatus) GetSQL() (string, error) {
 		sb.WriteString(strconv.Itoa(*s.limit))
 	}
 
+	if err := appendReturning(&sb, s.scope, s.returning); err != nil {
+		return "", err
+	}
+
 	return sb.String(), nil
 }
 
+// Returning appends a RETURNING clause, and the statement is executed as a query to fetch the returned rows.
+// It is supported by PostgreSQL and SQLite.
+func (s deleteStatus) Returning(fields ...Field) toReturningWithContext {
+	s.returning = fields
+	return returningStatus{database: s.scope.Database, statement: s}
+}
+
 func (s deleteStatus) WithContext(ctx context.Context) toDeleteFinal {
 	s.ctx = ctx
 	return s
 }
 
 func (s deleteStatus) Execute() (sql.Result, error) {
-	sqlString, err := s.GetSQL()
-	if err != nil {
+	return s.scope.Database.executeStatement(s.ctx, s)
+}
+
+type deleteInBatchesStatus struct {
+	status    deleteStatus
+	batchSize int
+}
+
+type deleteInBatches interface {
+	toDeleteInBatchesFinal
+	WithContext(ctx context.Context) toDeleteInBatchesFinal
+}
+
+type toDeleteInBatchesFinal interface {
+	Execute() (result sql.Result, err error)
+}
+
+// DeleteInBatches deletes the rows matching the condition by DELETE ... LIMIT batchSize statements,
+// each executed in its own transaction, so that no lock is held for long while purging a large number of rows.
+// The deletion stops once a batch deletes fewer rows than batchSize.
+// It is supported by MySQL, and by SQLite compiled with SQLITE_ENABLE_UPDATE_DELETE_LIMIT.
+func (d *database) DeleteInBatches(table Table, condition BooleanExpression, batchSize int) deleteInBatches {
+	status := deleteStatus{scope: scope{Database: d, Tables: []Table{table}}, where: condition}
+	return deleteInBatchesStatus{status: status, batchSize: batchSize}
+}
+
+func (s deleteInBatchesStatus) WithContext(ctx context.Context) toDeleteInBatchesFinal {
+	s.status.ctx = ctx
+	return s
+}
+
+// Execute runs the batches until all the matching rows are deleted. On error, the result counts
+// the rows deleted by the batches committed before it.
+func (s deleteInBatchesStatus) Execute() (sql.Result, error) {
+	if s.batchSize <= 0 {
+		return nil, errors.New("batch size must be positive")
+	}
+	if err := checkDialect(s.status.scope, "DELETE with LIMIT", dialectMySQL, dialectSqlite3); err != nil {
 		return nil, err
 	}
-	return s.scope.Database.ExecuteContext(s.ctx, sqlString)
+	s.status.limit = &s.batchSize
+
+	var total batchResult
+	for {
+		if s.status.ctx != nil {
+			if err := s.status.ctx.Err(); err != nil {
+				return total, err
+			}
+		}
+		var rowsAffected int64
+		err := s.status.scope.Database.BeginTx(s.status.ctx, nil, func(tx Transaction) error {
+			status := s.status
+			status.scope.Database = tx.(*database)
+			result, err := status.Execute()
+			if err != nil {
+				return err
+			}
+			rowsAffected, err = result.RowsAffected()
+			return err
+		})
+		if err != nil {
+			return total, err
+		}
+		total.rowsAffected += rowsAffected
+		if rowsAffected < int64(s.batchSize) {
+			return total, nil
+		}
+	}
 }
@@ -2,6 +2,7 @@ package sqlingo
 
 import (
 	"context"
+	"database/sql/driver"
 	"errors"
 	"testing"
 )
@@ -37,3 +38,72 @@ func TestDelete(t *testing.T) {
 	}
 	assertLastSql(t, "DELETE FROM `table1` WHERE #1#")
 }
+
+func TestDeleteAll(t *testing.T) {
+	db := newMockDatabase()
+	if _, err := db.DeleteFrom(Table1).Where().Execute(); err == nil {
+		t.Error("should fail without condition")
+	}
+	if _, err := db.DeleteFrom(Table1).Where(True(), True()).Execute(); err == nil {
+		t.Error("should fail with an always true condition")
+	}
+
+	if _, err := db.DeleteFrom(Table1).DeleteAll().Execute(); err != nil {
+		t.Error(err)
+	}
+	assertLastSql(t, "DELETE FROM `table1`")
+	if _, err := db.DeleteFrom(Table1).DeleteAll().OrderBy(Raw("#1#")).Limit(3).Execute(); err != nil {
+		t.Error(err)
+	}
+	assertLastSql(t, "DELETE FROM `table1` ORDER BY #1# LIMIT 3")
+}
+
+func TestDeleteInBatches(t *testing.T) {
+	db := newMockDatabase()
+	sharedMockConn.execResult = driver.RowsAffected(1)
+	defer func() {
+		sharedMockConn.execResult = nil
+	}()
+	var sqls []string
+	db.SetInterceptor(func(ctx context.Context, sql string, invoker InvokerFunc) error {
+		sqls = append(sqls, sql)
+		if len(sqls) == 3 {
+			return errors.New("error")
+		}
+		return invoker(ctx, sql)
+	})
+
+	// every batch deletes one row in the mock, so the batches of one row go on until the error
+	result, err := db.DeleteInBatches(Test, Test.F1.LessThan(10), 1).Execute()
+	if err == nil {
+		t.Error("should get error here")
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected != 2 {
+		t.Error(rowsAffected)
+	}
+	if len(sqls) != 3 {
+		t.Fatal(sqls)
+	}
+	assertEqual(t, sqls[0], "DELETE FROM `test` WHERE `f1` < 10 LIMIT 1")
+
+	sqls = nil
+	result, err = db.DeleteInBatches(Test, Test.F1.LessThan(10), 100).Execute()
+	if err != nil {
+		t.Error(err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected != 1 || len(sqls) != 1 {
+		t.Error(rowsAffected, sqls)
+	}
+	assertEqual(t, sqls[0], "DELETE FROM `test` WHERE `f1` < 10 LIMIT 100")
+
+	if _, err := db.DeleteInBatches(Test, Test.F1.LessThan(10), 0).Execute(); err == nil {
+		t.Error("should get error here")
+	}
+	if _, err := db.DeleteInBatches(Test, True(), 100).Execute(); err == nil {
+		t.Error("should get error here")
+	}
+	db.(*database).dialect = dialectPostgres
+	if _, err := db.DeleteInBatches(Test, Test.F1.LessThan(10), 100).Execute(); err == nil {
+		t.Error("should get error here")
+	}
+}
@@ -1,5 +1,10 @@
 package sqlingo
 
+import (
+	"fmt"
+	"strings"
+)
+
 type dialect int
 
 const (
@@ -12,13 +17,52 @@ const (
 	dialectCount
 )
 
+// Dialect is the SQL dialect of a database, which decides the quoting, placeholders and syntax of the generated SQL.
+type Dialect int
+
+// The dialects that can be set explicitly with OpenWithDialect or SetDialect.
+const (
+	DialectUnknown Dialect = iota
+	DialectMySQL
+	DialectSQLite
+	DialectPostgres
+	DialectMSSQL
+)
+
+func (d Dialect) String() string {
+	return d.dialect().String()
+}
+
+// dialect converts d to the internal dialect, treating the values out of range as unknown.
+func (d Dialect) dialect() dialect {
+	if d < 0 || dialect(d) >= dialectCount {
+		return dialectUnknown
+	}
+	return dialect(d)
+}
+
 type dialectArray [dialectCount]string
 
+var dialectNames = dialectArray{
+	dialectUnknown:  "unknown",
+	dialectMySQL:    "MySQL",
+	dialectSqlite3:  "SQLite",
+	dialectPostgres: "PostgreSQL",
+	dialectMSSQL:    "SQL Server",
+}
+
+func (d dialect) String() string {
+	if d < 0 || d >= dialectCount {
+		return dialectNames[dialectUnknown]
+	}
+	return dialectNames[d]
+}
+
 func getDialectFromDriverName(driverName string) dialect {
 	switch driverName {
 	case "mysql":
 		return dialectMySQL
-	case "sqlite3":
+	case "sqlite3", "sqlite":
 		return dialectSqlite3
 	case "postgres":
 		return dialectPostgres
@@ -28,3 +72,28 @@ func getDialectFromDriverName(driverName string) dialect {
 		return dialectUnknown
 	}
 }
+
+func getDialect(scope scope) dialect {
+	if scope.Database == nil {
+		return dialectUnknown
+	}
+	return scope.Database.dialect
+}
+
+// checkDialect returns an error if the feature is used with a database whose dialect is not one of supported.
+// The check is skipped for databases of unknown dialect.
+func checkDialect(scope scope, feature string, supported ...dialect) error {
+	current := getDialect(scope)
+	if current == dialectUnknown {
+		return nil
+	}
+	names := make([]string, len(supported))
+	for i, d := range supported {
+		if d == current {
+			return nil
+		}
+		names[i] = d.String()
+	}
+	return fmt.Errorf("%s is only supported by %s but the database dialect is %s",
+		feature, strings.Join(names, ", "), current)
+}
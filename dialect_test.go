@@ -6,6 +6,7 @@ func TestDialect(t *testing.T) {
 	nameToDialect := map[string]dialect{
 		"mysql":           dialectMySQL,
 		"sqlite3":         dialectSqlite3,
+		"sqlite":          dialectSqlite3,
 		"postgres":        dialectPostgres,
 		"sqlserver":       dialectMSSQL,
 		"mssql":           dialectMSSQL,
@@ -18,3 +19,97 @@ func TestDialect(t *testing.T) {
 		}
 	}
 }
+
+func TestIdentifierQuoting(t *testing.T) {
+	db := newMockDatabase()
+	expected := map[dialect]string{
+		dialectMySQL:    "UPDATE `table1` SET `field2` = 2 WHERE `field1` = 1",
+		dialectSqlite3:  `UPDATE "table1" SET "field2" = 2 WHERE "field1" = 1`,
+		dialectPostgres: `UPDATE "table1" SET "field2" = 2 WHERE "field1" = 1`,
+		dialectMSSQL:    "UPDATE [table1] SET [field2] = 2 WHERE [field1] = 1",
+	}
+	for dialect, expectedSql := range expected {
+		db.(*database).dialect = dialect
+		_, _ = db.Update(table1).Set(field2, 2).Where(field1.Equals(1)).Execute()
+		assertLastSql(t, expectedSql)
+	}
+}
+
+func TestCheckDialect(t *testing.T) {
+	assertEqual(t, dialectPostgres.String(), "PostgreSQL")
+	assertEqual(t, dialectCount.String(), "unknown")
+
+	db := newMockDatabase()
+	s := scope{Database: db.(*database)}
+	if err := checkDialect(s, "FEATURE", dialectMySQL); err != nil {
+		t.Error(err)
+	}
+	err := checkDialect(s, "FEATURE", dialectPostgres, dialectMSSQL)
+	if err == nil || err.Error() != "FEATURE is only supported by PostgreSQL, SQL Server but the database dialect is MySQL" {
+		t.Error(err)
+	}
+	if err := checkDialect(scope{}, "FEATURE", dialectPostgres); err != nil {
+		t.Error(err)
+	}
+
+	db.(*database).dialect = dialectMSSQL
+	_, err = Test.F2.Regexp("^a").GetSQL(scope{Database: db.(*database)})
+	if err == nil || err.Error() != "REGEXP is only supported by MySQL, SQLite, PostgreSQL but the database dialect is SQL Server" {
+		t.Error(err)
+	}
+}
+
+func TestSqlite(t *testing.T) {
+	db := newMockDatabase()
+	db.(*database).dialect = dialectSqlite3
+
+	_, _ = db.SelectFrom(Test).Limit(5).Offset(10).FetchAll()
+	assertLastSql(t, `SELECT * FROM "test" LIMIT 5 OFFSET 10`)
+
+	sqlString, args, err := db.InsertInto(Test).Fields(Test.F1, Test.F2).Values(1, "a").
+		OnConflict(Test.F1).DoUpdateSet(Test.F2, "b").GetSQLWithArgs()
+	if err != nil {
+		t.Error(err)
+	}
+	assertEqual(t, sqlString, `INSERT INTO "test" ("f1", "f2") VALUES (?, ?) ON CONFLICT ("f1") DO UPDATE SET "f2" = ?`)
+	if len(args) != 3 {
+		t.Error(args)
+	}
+}
+
+func TestOpenWithDialect(t *testing.T) {
+	db, err := OpenWithDialect("sqlingo-mock", "dummy", DialectPostgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = db.SelectFrom(Test).Where(Test.F1.Equals(1)).Limit(1).FetchAll()
+	assertLastSql(t, `SELECT * FROM "test" WHERE "f1" = 1 LIMIT 1`)
+
+	db.SetDialect(DialectMSSQL)
+	_, _ = db.SelectFrom(Test).Where(Test.F1.Equals(1)).Limit(1).FetchAll()
+	assertLastSql(t, `SELECT TOP (1) * FROM [test] WHERE [f1] = 1`)
+
+	if _, err := OpenWithDialect("unknowndb", "unknown", DialectMySQL); err == nil {
+		t.Error("should fail for unknown driver")
+	}
+}
+
+func TestExportedDialect(t *testing.T) {
+	exportedToDialect := map[Dialect]dialect{
+		DialectUnknown:  dialectUnknown,
+		DialectMySQL:    dialectMySQL,
+		DialectSQLite:   dialectSqlite3,
+		DialectPostgres: dialectPostgres,
+		DialectMSSQL:    dialectMSSQL,
+		Dialect(-1):     dialectUnknown,
+		Dialect(100):    dialectUnknown,
+	}
+	for exported, d := range exportedToDialect {
+		if exported.dialect() != d {
+			t.Errorf("dialect of %d should be %s, got %s", exported, d, exported.dialect())
+		}
+	}
+	if DialectSQLite.String() != "SQLite" {
+		t.Error(DialectSQLite.String())
+	}
+}
@@ -1,11 +1,16 @@
 package sqlingo
 
 import (
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"math/big"
 	"reflect"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 	"unsafe"
 )
 
@@ -16,6 +21,8 @@ type Expression interface {
 	// get the SQL string
 	GetSQL(scope scope) (string, error)
 	getOperatorPriority() priority
+	// get the column of a field, or nil for other expressions
+	getColumn() *column
 
 	// <> operator
 	NotEquals(other interface{}) BooleanExpression
@@ -40,9 +47,18 @@ type Expression interface {
 	NotIn(values ...interface{}) BooleanExpression
 	Between(min interface{}, max interface{}) BooleanExpression
 	NotBetween(min interface{}, max interface{}) BooleanExpression
-	Desc() OrderBy
+	Asc() SortOrder
+	Desc() SortOrder
 
 	As(alias string) Alias
+	Count() NumberExpression
+	CountDistinct() NumberExpression
+	Distinct() UnknownExpression
+	AnyValue() UnknownExpression
+	JSON() JSONPath
+	Cast(sqlType string) UnknownExpression
+	Collate(collation string) UnknownExpression
+	Over(partitionBy []Expression, orderBy []OrderBy) UnknownExpression
 
 	If(trueValue interface{}, falseValue interface{}) UnknownExpression
 	IfNull(altValue interface{}) UnknownExpression
@@ -71,11 +87,22 @@ type NumberExpression interface {
 	Div(other interface{}) NumberExpression
 	IntDiv(other interface{}) NumberExpression
 	Mod(other interface{}) NumberExpression
+	Negate() NumberExpression
+	BitAnd(other interface{}) NumberExpression
+	BitOr(other interface{}) NumberExpression
+	BitXor(other interface{}) NumberExpression
+	ShiftLeft(other interface{}) NumberExpression
+	ShiftRight(other interface{}) NumberExpression
 
 	Sum() NumberExpression
 	Avg() NumberExpression
 	Min() UnknownExpression
 	Max() UnknownExpression
+
+	Round(decimals interface{}) NumberExpression
+	Ceil() NumberExpression
+	Floor() NumberExpression
+	Abs() NumberExpression
 }
 
 // StringExpression is the interface of an SQL expression with string value.
@@ -84,15 +111,30 @@ type StringExpression interface {
 	Min() UnknownExpression
 	Max() UnknownExpression
 	Like(other interface{}) BooleanExpression
+	LikeEscape(pattern interface{}, escape string) BooleanExpression
+	NotLike(other interface{}) BooleanExpression
+	ILike(other interface{}) BooleanExpression
+	Regexp(pattern interface{}) BooleanExpression
 	Contains(substring string) BooleanExpression
+	Position(substring interface{}) NumberExpression
 	Concat(other interface{}) StringExpression
 	IfEmpty(altValue interface{}) StringExpression
 	IsEmpty() BooleanExpression
 	Lower() StringExpression
 	Upper() StringExpression
+	Reverse() StringExpression
 	Left(count interface{}) StringExpression
 	Right(count interface{}) StringExpression
 	Trim() StringExpression
+	Ltrim() StringExpression
+	Rtrim() StringExpression
+	TrimChars(chars string) StringExpression
+	Substring(start interface{}, length interface{}) StringExpression
+	Replace(from interface{}, to interface{}) StringExpression
+	LPad(length interface{}, padStr ...interface{}) StringExpression
+	RPad(length interface{}, padStr ...interface{}) StringExpression
+	Repeat(count interface{}) StringExpression
+	GroupConcat(separator string) StringExpression
 }
 
 type ArrayExpression interface {
@@ -103,6 +145,14 @@ type DateExpression interface {
 	Expression
 	Min() UnknownExpression
 	Max() UnknownExpression
+	DateAdd(interval string, value interface{}) DateExpression
+	DateSub(interval string, value interface{}) DateExpression
+	Year() NumberExpression
+	Month() NumberExpression
+	Day() NumberExpression
+	Hour() NumberExpression
+	Minute() NumberExpression
+	Second() NumberExpression
 }
 
 // UnknownExpression is the interface of an SQL expression with unknown value.
@@ -118,22 +168,56 @@ type UnknownExpression interface {
 	Div(other interface{}) NumberExpression
 	IntDiv(other interface{}) NumberExpression
 	Mod(other interface{}) NumberExpression
+	DateAdd(interval string, value interface{}) DateExpression
+	DateSub(interval string, value interface{}) DateExpression
+	Year() NumberExpression
+	Month() NumberExpression
+	Day() NumberExpression
+	Hour() NumberExpression
+	Minute() NumberExpression
+	Second() NumberExpression
+	Negate() NumberExpression
+	BitAnd(other interface{}) NumberExpression
+	BitOr(other interface{}) NumberExpression
+	BitXor(other interface{}) NumberExpression
+	ShiftLeft(other interface{}) NumberExpression
+	ShiftRight(other interface{}) NumberExpression
 
 	Sum() NumberExpression
 	Avg() NumberExpression
 	Min() UnknownExpression
 	Max() UnknownExpression
 
+	Round(decimals interface{}) NumberExpression
+	Ceil() NumberExpression
+	Floor() NumberExpression
+	Abs() NumberExpression
+
 	Like(other interface{}) BooleanExpression
+	LikeEscape(pattern interface{}, escape string) BooleanExpression
+	NotLike(other interface{}) BooleanExpression
+	ILike(other interface{}) BooleanExpression
+	Regexp(pattern interface{}) BooleanExpression
 	Contains(substring string) BooleanExpression
+	Position(substring interface{}) NumberExpression
 	Concat(other interface{}) StringExpression
 	IfEmpty(altValue interface{}) StringExpression
 	IsEmpty() BooleanExpression
 	Lower() StringExpression
 	Upper() StringExpression
+	Reverse() StringExpression
 	Left(count interface{}) StringExpression
 	Right(count interface{}) StringExpression
 	Trim() StringExpression
+	Ltrim() StringExpression
+	Rtrim() StringExpression
+	TrimChars(chars string) StringExpression
+	Substring(start interface{}, length interface{}) StringExpression
+	Replace(from interface{}, to interface{}) StringExpression
+	LPad(length interface{}, padStr ...interface{}) StringExpression
+	RPad(length interface{}, padStr ...interface{}) StringExpression
+	Repeat(count interface{}) StringExpression
+	GroupConcat(separator string) StringExpression
 }
 
 type expression struct {
@@ -143,6 +227,12 @@ type expression struct {
 	isTrue   bool
 	isFalse  bool
 	isBool   bool
+	// constant NULL or constant non-null value, used to fold IS [NOT] NULL
+	isNull     bool
+	isConstant bool
+	// DISTINCT argument of an aggregator, and the aggregator over it, which cannot be used over a window
+	isDistinct          bool
+	isDistinctAggregate bool
 }
 
 func (e expression) GetTable() Table {
@@ -153,6 +243,7 @@ type scope struct {
 	Database *database
 	Tables   []Table
 	lastJoin *join
+	args     *argList // collects the bound arguments of a parameterized statement, nil to inline values
 }
 
 func staticExpression(sql string, priority priority, isBool bool) expression {
@@ -179,14 +270,90 @@ func False() BooleanExpression {
 	}
 }
 
-// Raw create a raw SQL statement
-func Raw(sql string) UnknownExpression {
+// Null creates a NULL literal.
+func Null() UnknownExpression {
 	return expression{
-		sql:      sql,
+		sql:    "NULL",
+		isNull: true,
+	}
+}
+
+// Value creates a literal expression of the value, which is quoted the same way as the operands.
+// Nil values and zero times become NULL.
+func Value(value interface{}) UnknownExpression {
+	isConstant := false
+	switch value.(type) {
+	case Expression, Assignment, toSelectFinal, Table, CaseExpression:
+	default:
+		if sql, _, err := getSQL(scope{}, value); err == nil && sql == "NULL" {
+			return Null()
+		}
+		isConstant = true
+	}
+	return expression{
+		builder: func(scope scope) (string, error) {
+			sql, _, err := getSQL(scope, value)
+			return sql, err
+		},
+		isConstant: isConstant,
+	}
+}
+
+// Raw create a raw SQL statement. If args are given, each ? placeholder outside quoted strings
+// is replaced by the corresponding argument, which is quoted the same way as the operands.
+func Raw(sql string, args ...interface{}) UnknownExpression {
+	if len(args) == 0 {
+		return expression{
+			sql:      sql,
+			priority: 99,
+		}
+	}
+	return expression{
+		builder: func(scope scope) (string, error) {
+			return bindRawArgs(scope, sql, args)
+		},
 		priority: 99,
 	}
 }
 
+func bindRawArgs(scope scope, sql string, args []interface{}) (string, error) {
+	var sb strings.Builder
+	sb.Grow(len(sql) + 16*len(args))
+	var quote byte
+	n := 0
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && i+1 < len(sql) {
+				sb.WriteByte(c)
+				i++
+				c = sql[i]
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '?':
+			if n >= len(args) {
+				return "", errors.New("too few arguments for the placeholders of raw SQL")
+			}
+			argSql, _, err := getSQL(scope, args[n])
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(argSql)
+			n++
+			continue
+		}
+		sb.WriteByte(c)
+	}
+	if n != len(args) {
+		return "", errors.New("too many arguments for the placeholders of raw SQL")
+	}
+	return sb.String(), nil
+}
+
 // And creates an expression with AND operator.
 func And(expressions ...BooleanExpression) (result BooleanExpression) {
 	if len(expressions) == 0 {
@@ -219,14 +386,173 @@ func Or(expressions ...BooleanExpression) (result BooleanExpression) {
 	return
 }
 
+// Exists creates an expression of EXISTS (subquery).
+func Exists(query toSelectFinal) BooleanExpression {
+	return existsExpression("EXISTS ", query)
+}
+
+// NotExists creates an expression of NOT EXISTS (subquery).
+func NotExists(query toSelectFinal) BooleanExpression {
+	return existsExpression("NOT EXISTS ", query)
+}
+
+func existsExpression(operator string, query toSelectFinal) expression {
+	return expression{
+		builder: func(scope scope) (string, error) {
+			sql, _, err := getSQL(scope, query)
+			if err != nil {
+				return "", err
+			}
+			return operator + sql, nil
+		},
+		// the predicate is self-delimited by the parentheses of the subquery
+		isBool: true,
+	}
+}
+
+// aliasExpression is an expression with a column alias. It renders as "expr AS alias" in the field list,
+// and as getOperandSQL when used as an operand.
+type aliasExpression struct {
+	expression
+	alias       string
+	quotedAlias bool
+}
+
+// quoted returns the alias expression with the alias quoted as an identifier.
+func (a aliasExpression) quoted() aliasExpression {
+	a.quotedAlias = true
+	return a
+}
+
 func (e expression) As(name string) Alias {
+	return aliasExpression{expression: e, alias: name}
+}
+
+// getOperandSQL returns the SQL of the alias used as an operand or in ORDER BY, which is the underlying
+// expression if alias inlining is enabled, or a reference to the alias otherwise.
+func (a aliasExpression) getOperandSQL(scope scope) (string, priority, error) {
+	if scope.Database != nil && scope.Database.aliasInlining {
+		sql, err := a.expression.GetSQL(scope)
+		return sql, a.priority, err
+	}
+	if a.quotedAlias {
+		return quoteIdentifier(a.alias)[getDialect(scope)], 0, nil
+	}
+	return a.alias, 0, nil
+}
+
+func (a aliasExpression) GetSQL(scope scope) (string, error) {
+	expressionSql, err := a.expression.GetSQL(scope)
+	if err != nil {
+		return "", err
+	}
+	if a.quotedAlias {
+		return expressionSql + " AS " + quoteIdentifier(a.alias)[getDialect(scope)], nil
+	}
+	return expressionSql + " AS " + a.alias, nil
+}
+
+// nullableExpression marks a projected column whose NULLs are scanned as the zero values of the destinations.
+// The table and the column of the wrapped field are kept, e.g. for aliasing the duplicate column names.
+type nullableExpression struct {
+	expression
+	wrapped Expression
+}
+
+// Nullable wraps a field in the select list, so that a NULL in its column leaves the zero value in a plain
+// destination such as int or time.Time instead of failing the scan. Pointer and sql.Null* destinations
+// are not affected as they hold NULLs already.
+func Nullable(expr Expression) UnknownExpression {
+	if nullable, ok := expr.(nullableExpression); ok {
+		return nullable
+	}
+	e := expression{
+		builder:  expr.GetSQL,
+		priority: expr.getOperatorPriority(),
+	}
+	switch wrapped := expr.(type) {
+	case expression:
+		e.isBool = wrapped.isBool
+	case *actualField:
+		e.isBool = wrapped.isBool
+	}
+	return nullableExpression{expression: e, wrapped: expr}
+}
+
+func (e nullableExpression) GetTable() Table {
+	if field, ok := e.wrapped.(Field); ok {
+		return field.GetTable()
+	}
+	return nil
+}
+
+func (e nullableExpression) getColumn() *column {
+	return e.wrapped.getColumn()
+}
+
+func (e nullableExpression) As(name string) Alias {
+	return e.alias(e.wrapped.As(name).(aliasExpression))
+}
+
+// alias wraps the alias of the wrapped expression, which is no longer a column of the table.
+func (e nullableExpression) alias(alias aliasExpression) nullableExpression {
+	return nullableExpression{expression: expression{
+		builder:  alias.GetSQL,
+		priority: e.priority,
+	}, wrapped: alias}
+}
+
+func (e expression) Cast(sqlType string) UnknownExpression {
+	return Cast(e, sqlType)
+}
+
+// Collate creates an expression of the value with the collation, e.g. col COLLATE `utf8mb4_bin` for a case-sensitive
+// comparison. The collation is quoted as an identifier, so that names such as "en_US.utf8" on PostgreSQL are allowed,
+// except on SQL Server, where it must be a plain identifier.
+func (e expression) Collate(collation string) UnknownExpression {
+	if !isCollationName(collation) {
+		return errorExpression(fmt.Errorf("invalid collation %q", collation))
+	}
 	return expression{builder: func(scope scope) (string, error) {
-		expressionSql, err := e.GetSQL(scope)
-		if err != nil {
-			return "", err
+		dialect := getDialect(scope)
+		name := quoteIdentifier(collation)[dialect]
+		if dialect == dialectMSSQL {
+			if !isPlainIdentifier(collation) {
+				return "", fmt.Errorf("invalid collation %q", collation)
+			}
+			name = collation
 		}
-		return expressionSql + " AS " + name, nil
-	}}
+		return e.prefixSuffixExpression("", " COLLATE "+name, 2, false).GetSQL(scope)
+	}, priority: 2}
+}
+
+// isCollationName reports whether s consists of ASCII letters, digits and the characters _ . - @,
+// which covers the collation names of the dialects, e.g. utf8mb4_0900_ai_ci, en_US.utf8 and und-x-icu.
+func isCollationName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.IndexByte("_.-@", c) >= 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// isPlainIdentifier reports whether s consists of ASCII letters, digits and underscores, not starting with a digit.
+func isPlainIdentifier(s string) bool {
+	if s == "" || s[0] >= '0' && s[0] <= '9' {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_') {
+			return false
+		}
+	}
+	return true
 }
 
 func (e expression) If(trueValue interface{}, falseValue interface{}) UnknownExpression {
@@ -253,6 +579,17 @@ func (e expression) Upper() StringExpression {
 	return function("UPPER", e)
 }
 
+// Reverse creates an expression of the string with the characters in reverse order, e.g. for grouping emails
+// by the domain with an index on REVERSE(email). SQLite has no REVERSE function.
+func (e expression) Reverse() StringExpression {
+	return expression{builder: func(scope scope) (string, error) {
+		if err := checkDialect(scope, "REVERSE", dialectMySQL, dialectPostgres, dialectMSSQL); err != nil {
+			return "", err
+		}
+		return function("REVERSE", e).GetSQL(scope)
+	}}
+}
+
 func (e expression) Left(count interface{}) StringExpression {
 	return function("LEFT", e, count)
 }
@@ -265,6 +602,118 @@ func (e expression) Trim() StringExpression {
 	return function("TRIM", e)
 }
 
+// Ltrim creates an expression of the string with the leading spaces removed.
+func (e expression) Ltrim() StringExpression {
+	return function("LTRIM", e)
+}
+
+// Rtrim creates an expression of the string with the trailing spaces removed.
+func (e expression) Rtrim() StringExpression {
+	return function("RTRIM", e)
+}
+
+// TrimChars creates an expression of the string with the chars removed from both ends, i.e. TRIM('chars' FROM str),
+// or TRIM(str, 'chars') on SQLite. Note that MySQL removes the repetitions of chars as a whole string,
+// while the other databases remove any of the characters in chars.
+func (e expression) TrimChars(chars string) StringExpression {
+	return expression{builder: func(scope scope) (string, error) {
+		sql, err := e.GetSQL(scope)
+		if err != nil {
+			return "", err
+		}
+		charsSql, _, err := getSQL(scope, chars)
+		if err != nil {
+			return "", err
+		}
+		if getDialect(scope) == dialectSqlite3 {
+			return "TRIM(" + sql + ", " + charsSql + ")", nil
+		}
+		return "TRIM(" + charsSql + " FROM " + sql + ")", nil
+	}}
+}
+
+func (e expression) Replace(from interface{}, to interface{}) StringExpression {
+	return function("REPLACE", e, from, to)
+}
+
+// LPad creates an expression of the string left-padded to the length with padStr, which is a single space
+// if omitted and can be a column, e.g. LPAD(str, 5, '0'). The string is truncated if longer than the length.
+// SQLite and SQL Server have no LPAD, and an error is returned there.
+func (e expression) LPad(length interface{}, padStr ...interface{}) StringExpression {
+	return e.pad("LPAD", length, padStr)
+}
+
+// RPad is like LPad, but pads the string on the right.
+func (e expression) RPad(length interface{}, padStr ...interface{}) StringExpression {
+	return e.pad("RPAD", length, padStr)
+}
+
+func (e expression) pad(name string, length interface{}, padStr []interface{}) expression {
+	var pad interface{} = " "
+	if len(padStr) > 0 {
+		pad = padStr[0]
+	}
+	return expression{builder: func(scope scope) (string, error) {
+		if err := checkDialect(scope, name, dialectMySQL, dialectPostgres); err != nil {
+			return "", err
+		}
+		return function(name, e, length, pad).GetSQL(scope)
+	}}
+}
+
+// Repeat creates an expression of the string repeated count times, i.e. REPEAT(str, count),
+// or REPLICATE(str, count) on SQL Server. SQLite has no such function, and an error is returned there.
+func (e expression) Repeat(count interface{}) StringExpression {
+	return expression{builder: func(scope scope) (string, error) {
+		if err := checkDialect(scope, "REPEAT", dialectMySQL, dialectPostgres, dialectMSSQL); err != nil {
+			return "", err
+		}
+		name := "REPEAT"
+		if getDialect(scope) == dialectMSSQL {
+			name = "REPLICATE"
+		}
+		return function(name, e, count).GetSQL(scope)
+	}}
+}
+
+var groupConcatFunctions = dialectArray{
+	dialectUnknown:  "STRING_AGG",
+	dialectMySQL:    "GROUP_CONCAT",
+	dialectSqlite3:  "GROUP_CONCAT",
+	dialectPostgres: "STRING_AGG",
+	dialectMSSQL:    "STRING_AGG",
+}
+
+// GroupConcat concatenates the values of the group with the separator, which is a comma if empty.
+func (e expression) GroupConcat(separator string) StringExpression {
+	return expression{builder: func(scope scope) (string, error) {
+		sql, err := e.GetSQL(scope)
+		if err != nil {
+			return "", err
+		}
+		dialect := getDialect(scope)
+		name := groupConcatFunctions[dialect]
+		if dialect == dialectMySQL {
+			if separator == "" {
+				return name + "(" + sql + ")", nil
+			}
+			return name + "(" + sql + " SEPARATOR " + quoteString(separator) + ")", nil
+		}
+		if separator == "" {
+			separator = ","
+		}
+		return name + "(" + sql + ", " + quoteString(separator) + ")", nil
+	}}
+}
+
+// Substring extracts the substring from the 1-based start position, to the end of the string if length is nil.
+func (e expression) Substring(start interface{}, length interface{}) StringExpression {
+	if length == nil {
+		return function("SUBSTRING", e, start)
+	}
+	return function("SUBSTRING", e, start, length)
+}
+
 func (e expression) CharLength() NumberExpression {
 	return function("CHAR_LENGTH", e)
 }
@@ -308,6 +757,15 @@ func quoteIdentifier(identifier string) (result dialectArray) {
 	return
 }
 
+// quoteDialectString quotes a string literal in the dialect, doubling the quotes on those where
+// a backslash is not special in the standard string literals.
+func quoteDialectString(dialect dialect, s string) string {
+	if dialect == dialectMySQL || dialect == dialectUnknown {
+		return quoteString(s)
+	}
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
 func quoteString(s string) string {
 	if s == "" {
 		return "''"
@@ -330,29 +788,44 @@ func quoteString(s string) string {
 }
 
 func getSQL(scope scope, value interface{}) (sql string, priority priority, err error) {
-	const mysqlTimeFormat = "2006-01-02 15:04:05.000000"
 	if value == nil {
 		sql = "NULL"
 		return
 	}
+	if scope.args != nil {
+		if arg, ok := getArg(value); ok {
+			sql = scope.args.add(getDialect(scope), arg)
+			return
+		}
+	}
 	switch value.(type) {
 	case int:
 		sql = strconv.Itoa(value.(int))
 	case string:
 		sql = quoteString(value.(string))
+	case SQLValuer:
+		if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr && v.IsNil() {
+			sql = "NULL"
+		} else {
+			sql = value.(SQLValuer).SQLValue()
+		}
+	case []byte:
+		sql = getBinarySQL(getDialect(scope), value.([]byte))
+	case aliasExpression:
+		sql, priority, err = value.(aliasExpression).getOperandSQL(scope)
 	case Expression:
 		sql, err = value.(Expression).GetSQL(scope)
 		priority = value.(Expression).getOperatorPriority()
 	case Assignment:
 		sql, err = value.(Assignment).GetSQL(scope)
 	case toSelectFinal:
-		sql, err = value.(toSelectFinal).GetSQL()
+		sql, err = getStatementSQL(scope.args, value.(toSelectFinal))
 		if err != nil {
 			return
 		}
 		sql = "(" + sql + ")"
 	case toUpdateFinal:
-		sql, err = value.(toUpdateFinal).GetSQL()
+		sql, err = getStatementSQL(scope.args, value.(toUpdateFinal))
 	case Table:
 		sql = value.(Table).GetSQL(scope)
 	case CaseExpression:
@@ -362,7 +835,7 @@ func getSQL(scope scope, value interface{}) (sql string, priority priority, err
 		if tm.IsZero() {
 			sql = "NULL"
 		} else {
-			tmStr := tm.Format(mysqlTimeFormat)
+			tmStr := tm.Format(getTimeFormat(scope))
 			sql = quoteString(tmStr)
 		}
 	case *time.Time:
@@ -370,16 +843,128 @@ func getSQL(scope scope, value interface{}) (sql string, priority priority, err
 		if tm == nil || tm.IsZero() {
 			sql = "NULL"
 		} else {
-			tmStr := tm.Format(mysqlTimeFormat)
+			tmStr := tm.Format(getTimeFormat(scope))
 			sql = quoteString(tmStr)
 		}
+	case driver.Valuer:
+		sql, err = getValuerSQL(scope, value.(driver.Valuer))
 	default:
+		var isNumeric bool
+		if sql, isNumeric, err = getNumericSQL(value); isNumeric {
+			return
+		}
 		v := reflect.ValueOf(value)
 		sql, priority, err = getSQLFromReflectValue(scope, v)
 	}
 	return
 }
 
+const defaultTimeFormat = "2006-01-02 15:04:05.000000"
+
+// getTimeFormat returns the layout of the time literals set by SetTimeFormat.
+func getTimeFormat(scope scope) string {
+	if scope.Database != nil && scope.Database.timeFormat != "" {
+		return scope.Database.timeFormat
+	}
+	return defaultTimeFormat
+}
+
+// getValuerSQL renders the value of the valuer, e.g. sql.NullString, which is NULL if the value is nil.
+func getValuerSQL(scope scope, valuer driver.Valuer) (string, error) {
+	if v := reflect.ValueOf(valuer); v.Kind() == reflect.Ptr && v.IsNil() {
+		return "NULL", nil
+	}
+	value, err := valuer.Value()
+	if err != nil {
+		return "", err
+	}
+	sql, _, err := getSQL(scope, value)
+	return sql, err
+}
+
+// getBinarySQL renders the bytes as a hex-encoded binary literal of the dialect.
+func getBinarySQL(dialect dialect, b []byte) string {
+	if b == nil {
+		return "NULL"
+	}
+	hexString := hex.EncodeToString(b)
+	switch dialect {
+	case dialectPostgres:
+		return `'\x` + hexString + "'"
+	case dialectMSSQL:
+		return "0x" + hexString
+	default:
+		return "X'" + hexString + "'"
+	}
+}
+
+// SQLValuer is the interface of the values which render their own SQL literals. The literal is emitted as is,
+// so it must be safe to splice into the statement. A decimal type, e.g. decimal.Decimal, can be wrapped to return
+// its String from SQLValue to be rendered as a numeric literal instead of the quoted string of its Value.
+type SQLValuer interface {
+	SQLValue() string
+}
+
+// getNumericSQL renders the arbitrary-precision numbers of math/big as unquoted numeric literals.
+// Third-party decimal types render themselves by implementing SQLValuer.
+func getNumericSQL(value interface{}) (sql string, ok bool, err error) {
+	switch value := value.(type) {
+	case *big.Int:
+		if value == nil {
+			return "NULL", true, nil
+		}
+		return value.String(), true, nil
+	case big.Int:
+		return value.String(), true, nil
+	case *big.Float:
+		if value == nil {
+			return "NULL", true, nil
+		}
+		return value.Text('g', -1), true, nil
+	case big.Float:
+		return value.Text('g', -1), true, nil
+	case *big.Rat:
+		if value == nil {
+			return "NULL", true, nil
+		}
+		sql, err = getRatSQL(value)
+		return sql, true, err
+	case big.Rat:
+		sql, err = getRatSQL(&value)
+		return sql, true, err
+	}
+	return "", false, nil
+}
+
+// getRatSQL renders the rational number as an exact decimal literal, which exists only if
+// the denominator has no prime factors other than 2 and 5.
+func getRatSQL(r *big.Rat) (string, error) {
+	if r.IsInt() {
+		return r.Num().String(), nil
+	}
+	denominator := new(big.Int).Set(r.Denom())
+	modulus := new(big.Int)
+	digits := 0
+	for _, factor := range []*big.Int{big.NewInt(2), big.NewInt(5)} {
+		count := 0
+		for {
+			quotient, remainder := new(big.Int).QuoRem(denominator, factor, modulus)
+			if remainder.Sign() != 0 {
+				break
+			}
+			denominator = quotient
+			count++
+		}
+		if count > digits {
+			digits = count
+		}
+	}
+	if denominator.Cmp(big.NewInt(1)) != 0 {
+		return "", fmt.Errorf("%s has no exact decimal representation", r.String())
+	}
+	return r.FloatString(digits), nil
+}
+
 func getSQLFromReflectValue(scope scope, v reflect.Value) (sql string, priority priority, err error) {
 	if v.Kind() == reflect.Ptr {
 		// dereference pointers
@@ -543,6 +1128,82 @@ func (e expression) Mod(other interface{}) NumberExpression {
 	return e.binaryOperation("%", other, 6, false)
 }
 
+func (e expression) BitAnd(other interface{}) NumberExpression {
+	return e.binaryOperation("&", other, 9, false)
+}
+
+func (e expression) BitOr(other interface{}) NumberExpression {
+	return e.binaryOperation("|", other, 10, false)
+}
+
+// BitXor creates an expression of bitwise XOR, which is # on PostgreSQL. SQLite lacking the operator
+// computes (a | b) - (a & b) instead.
+func (e expression) BitXor(other interface{}) NumberExpression {
+	xor := e.binaryOperation("^", other, 5, false)
+	postgresXor := e.binaryOperation("#", other, 5, false)
+	sqliteXor := e.BitOr(other).Sub(e.BitAnd(other))
+	return expression{builder: func(scope scope) (string, error) {
+		switch getDialect(scope) {
+		case dialectPostgres:
+			return postgresXor.GetSQL(scope)
+		case dialectSqlite3:
+			return sqliteXor.GetSQL(scope)
+		default:
+			return xor.GetSQL(scope)
+		}
+	}, priority: 7} // the lowest priority of the forms
+}
+
+func (e expression) ShiftLeft(other interface{}) NumberExpression {
+	return e.binaryOperation("<<", other, 8, false)
+}
+
+func (e expression) ShiftRight(other interface{}) NumberExpression {
+	return e.binaryOperation(">>", other, 8, false)
+}
+
+// Negate creates an expression of unary minus, e.g. -x, or -(a + b) for an operand of lower priority.
+func (e expression) Negate() NumberExpression {
+	negated := e.prefixSuffixExpression("-", "", 4, false)
+	return expression{builder: func(scope scope) (string, error) {
+		sql, err := negated.GetSQL(scope)
+		if err != nil {
+			return "", err
+		}
+		if strings.HasPrefix(sql, "--") {
+			// a negative operand would turn the minus into a comment
+			return "-(" + sql[1:] + ")", nil
+		}
+		return sql, nil
+	}, priority: 4}
+}
+
+func (e expression) Count() NumberExpression {
+	return Count(e)
+}
+
+func (e expression) CountDistinct() NumberExpression {
+	return expression{builder: func(scope scope) (string, error) {
+		sql, err := e.GetSQL(scope)
+		if err != nil {
+			return "", err
+		}
+		return "COUNT(DISTINCT " + sql + ")", nil
+	}, isDistinctAggregate: true}
+}
+
+// Distinct marks the expression as the DISTINCT argument of an aggregator, e.g. field.Distinct().Count()
+// for COUNT(DISTINCT field). See Over for the limitations of the distinct aggregators over a window.
+func (e expression) Distinct() UnknownExpression {
+	return expression{builder: func(scope scope) (string, error) {
+		sql, err := e.GetSQL(scope)
+		if err != nil {
+			return "", err
+		}
+		return "DISTINCT " + sql, nil
+	}, isDistinct: true}
+}
+
 func (e expression) Sum() NumberExpression {
 	return function("SUM", e)
 }
@@ -551,6 +1212,18 @@ func (e expression) Avg() NumberExpression {
 	return function("AVG", e)
 }
 
+// AnyValue creates an expression of ANY_VALUE aggregator, which selects a non-grouped column
+// under ONLY_FULL_GROUP_BY of MySQL. It is MIN on the other dialects.
+func (e expression) AnyValue() UnknownExpression {
+	return dialectFunction(dialectArray{
+		dialectUnknown:  "ANY_VALUE",
+		dialectMySQL:    "ANY_VALUE",
+		dialectSqlite3:  "MIN",
+		dialectPostgres: "MIN",
+		dialectMSSQL:    "MIN",
+	}, e)
+}
+
 func (e expression) Min() UnknownExpression {
 	return function("MIN", e)
 }
@@ -559,16 +1232,111 @@ func (e expression) Max() UnknownExpression {
 	return function("MAX", e)
 }
 
+// Round rounds the value to the specified number of decimals, or to an integer if decimals is nil.
+func (e expression) Round(decimals interface{}) NumberExpression {
+	if decimals == nil {
+		return function("ROUND", e)
+	}
+	return function("ROUND", e, decimals)
+}
+
+func (e expression) Ceil() NumberExpression {
+	return function("CEILING", e)
+}
+
+func (e expression) Floor() NumberExpression {
+	return function("FLOOR", e)
+}
+
+func (e expression) Abs() NumberExpression {
+	return function("ABS", e)
+}
+
 func (e expression) Like(other interface{}) BooleanExpression {
 	return e.binaryOperation("LIKE", other, 11, true)
 }
 
+// LikeEscape matches the pattern where the escape character, a single character given instead of the default
+// backslash, makes the following % or _ match literally, i.e. LIKE pattern ESCAPE 'escape'.
+func (e expression) LikeEscape(pattern interface{}, escape string) BooleanExpression {
+	like := e.binaryOperation("LIKE", pattern, 11, true)
+	return expression{builder: func(scope scope) (string, error) {
+		if utf8.RuneCountInString(escape) != 1 {
+			return "", fmt.Errorf("LIKE escape %q is not a single character", escape)
+		}
+		likeSql, err := like.GetSQL(scope)
+		if err != nil {
+			return "", err
+		}
+		return likeSql + " ESCAPE " + quoteDialectString(getDialect(scope), escape), nil
+	}, priority: 11, isBool: true}
+}
+
+func (e expression) NotLike(other interface{}) BooleanExpression {
+	return e.binaryOperation("NOT LIKE", other, 11, true)
+}
+
+// ILike matches the pattern case-insensitively, using ILIKE on PostgreSQL and comparing the lower cases elsewhere.
+func (e expression) ILike(other interface{}) BooleanExpression {
+	iLike := e.binaryOperation("ILIKE", other, 11, true)
+	lowerLike := function("LOWER", e).binaryOperation("LIKE", function("LOWER", other), 11, true)
+	return expression{builder: func(scope scope) (string, error) {
+		if getDialect(scope) == dialectPostgres {
+			return iLike.GetSQL(scope)
+		}
+		return lowerLike.GetSQL(scope)
+	}, priority: 11, isBool: true}
+}
+
+var regexpOperators = dialectArray{
+	dialectUnknown:  "REGEXP",
+	dialectMySQL:    "REGEXP",
+	dialectSqlite3:  "REGEXP",
+	dialectPostgres: "~",
+	// SQL Server has no native regular expression operator
+}
+
+func (e expression) Regexp(pattern interface{}) BooleanExpression {
+	return expression{builder: func(scope scope) (string, error) {
+		if err := checkDialect(scope, "REGEXP", dialectMySQL, dialectSqlite3, dialectPostgres); err != nil {
+			return "", err
+		}
+		operator := regexpOperators[getDialect(scope)]
+		return e.binaryOperation(operator, pattern, 11, true).GetSQL(scope)
+	}, priority: 11, isBool: true}
+}
+
 func (e expression) Concat(other interface{}) StringExpression {
 	return Concat(e, other)
 }
 
 func (e expression) Contains(substring string) BooleanExpression {
-	return function("LOCATE", substring, e).GreaterThan(0)
+	return e.Position(substring).GreaterThan(0)
+}
+
+// Position creates an expression of the 1-based index of the first occurrence of the substring in the string,
+// or 0 if not found, e.g. LOCATE(substring, str) on MySQL and POSITION(substring IN str) on PostgreSQL.
+func (e expression) Position(substring interface{}) NumberExpression {
+	return expression{builder: func(scope scope) (string, error) {
+		switch getDialect(scope) {
+		case dialectPostgres:
+			sql, err := e.GetSQL(scope)
+			if err != nil {
+				return "", err
+			}
+			substringSql, _, err := getSQL(scope, substring)
+			if err != nil {
+				return "", err
+			}
+			return "POSITION(" + substringSql + " IN " + sql + ")", nil
+		case dialectSqlite3:
+			return function("INSTR", e, substring).GetSQL(scope)
+		case dialectMSSQL:
+			return function("CHARINDEX", substring, e).GetSQL(scope)
+		default:
+			return function("LOCATE", substring, e).GetSQL(scope)
+		}
+	}}
 }
 
 func (e expression) binaryOperation(operator string, value interface{}, priority priority, isBool bool) expression {
@@ -608,7 +1376,7 @@ func (e expression) binaryOperation(operator string, value interface{}, priority
 }
 
 func (e expression) prefixSuffixExpression(prefix string, suffix string, priority priority, isBool bool) expression {
-	if e.sql != "" {
+	if e.sql != "" && e.priority <= priority {
 		return expression{
 			sql:      prefix + e.sql + suffix,
 			priority: priority,
@@ -641,7 +1409,14 @@ func (e expression) prefixSuffixExpression(prefix string, suffix string, priorit
 }
 
 func (e expression) IsNull() BooleanExpression {
-	return e.prefixSuffixExpression("", " IS NULL", 11, true)
+	switch {
+	case e.isNull:
+		return True()
+	case e.isConstant || e.isTrue || e.isFalse:
+		return False()
+	default:
+		return e.prefixSuffixExpression("", " IS NULL", 11, true)
+	}
 }
 
 func (e expression) Not() BooleanExpression {
@@ -656,7 +1431,14 @@ func (e expression) Not() BooleanExpression {
 }
 
 func (e expression) IsNotNull() BooleanExpression {
-	return e.prefixSuffixExpression("", " IS NOT NULL", 11, true)
+	switch {
+	case e.isNull:
+		return False()
+	case e.isConstant || e.isTrue || e.isFalse:
+		return True()
+	default:
+		return e.prefixSuffixExpression("", " IS NOT NULL", 11, true)
+	}
 }
 
 func (e expression) IsTrue() BooleanExpression {
@@ -686,6 +1468,9 @@ func expandSliceValue(value reflect.Value) (result []interface{}) {
 		}
 	case reflect.Interface, reflect.Ptr:
 		result = append(result, expandSliceValue(value.Elem())...)
+	case reflect.Invalid:
+		// nil, or the element of a nil pointer
+		result = append(result, nil)
 	default:
 		result = append(result, value.Interface())
 	}
@@ -701,56 +1486,101 @@ func expandSliceValues(values []interface{}) (result []interface{}) {
 	return
 }
 
+// removeNullValues removes the NULLs from the values, and reports whether there was any.
+func removeNullValues(values []interface{}) (result []interface{}, hasNull bool) {
+	result = make([]interface{}, 0, len(values))
+	for _, value := range values {
+		if e, ok := value.(expression); value == nil || ok && e.isNull {
+			hasNull = true
+			continue
+		}
+		result = append(result, value)
+	}
+	return
+}
+
+// In creates an IN expression of the values, where slices are expanded. As nothing is IN a list by a NULL,
+// a NULL in the values is matched by IS NULL, e.g. In(1, nil, 2) is "x IS NULL OR x IN (1, 2)".
 func (e expression) In(values ...interface{}) BooleanExpression {
-	values = expandSliceValues(values)
+	values, hasNull := removeNullValues(expandSliceValues(values))
 	if len(values) == 0 {
+		if hasNull {
+			return e.IsNull()
+		}
 		return False()
 	}
 	joiner := func(exprSql, valuesSql string) string { return exprSql + " IN (" + valuesSql + ")" }
-	builder := e.getBuilder(e.Equals, joiner, values...)
-	return expression{builder: builder, priority: 11}
+	builder := e.getBuilder(e.Equals, joiner, " = ANY(", values...)
+	in := expression{builder: builder, priority: 11}
+	if hasNull {
+		return e.IsNull().Or(in)
+	}
+	return in
 }
 
+// NotIn creates a NOT IN expression of the values, where slices are expanded. A NULL in the values
+// excludes the NULLs by IS NOT NULL, e.g. NotIn(1, nil, 2) is "x IS NOT NULL AND x NOT IN (1, 2)".
 func (e expression) NotIn(values ...interface{}) BooleanExpression {
-	values = expandSliceValues(values)
+	values, hasNull := removeNullValues(expandSliceValues(values))
 	if len(values) == 0 {
+		if hasNull {
+			return e.IsNotNull()
+		}
 		return True()
 	}
 	joiner := func(exprSql, valuesSql string) string { return exprSql + " NOT IN (" + valuesSql + ")" }
-	builder := e.getBuilder(e.NotEquals, joiner, values...)
-	return expression{builder: builder, priority: 11}
+	builder := e.getBuilder(e.NotEquals, joiner, " <> ALL(", values...)
+	notIn := expression{builder: builder, priority: 11}
+	if hasNull {
+		return e.IsNotNull().And(notIn)
+	}
+	return notIn
 }
 
 type joinerFunc = func(exprSql, valuesSql string) string
 type booleanFunc = func(other interface{}) BooleanExpression
 type builderFunc = func(scope scope) (string, error)
 
-func (e expression) getBuilder(single booleanFunc, joiner joinerFunc, values ...interface{}) builderFunc {
+// getBuilder builds the IN or NOT IN expression. A bound list is collapsed into a single array argument
+// on PostgreSQL, e.g. x = ANY($1), so that the SQL does not vary with the length of the list.
+func (e expression) getBuilder(single booleanFunc, joiner joinerFunc, arrayOperator string, values ...interface{}) builderFunc {
 	return func(scope scope) (string, error) {
-		var valuesSql string
-		var err error
-
+		inline := scope.Database != nil && len(values) <= scope.Database.inlineThreshold
 		if len(values) == 1 {
-			value := values[0]
-			if selectStatus, ok := value.(toSelectFinal); ok {
-				// IN subquery
-				valuesSql, err = selectStatus.GetSQL()
-				if err != nil {
-					return "", err
-				}
-			} else {
+			if _, ok := values[0].(toSelectFinal); !ok {
 				// IN a single value
-				return single(value).GetSQL(scope)
+				if inline {
+					return single(inlineValue(values[0])).GetSQL(scope)
+				}
+				return single(values[0]).GetSQL(scope)
 			}
+		}
+
+		exprSql, err := e.GetSQL(scope)
+		if err != nil {
+			return "", err
+		}
+
+		var valuesSql string
+		if selectStatus, ok := values[0].(toSelectFinal); ok && len(values) == 1 {
+			// IN subquery
+			valuesSql, err = getStatementSQL(scope.args, selectStatus)
 		} else {
 			// IN a list
-			valuesSql, err = commaValues(scope, values)
-			if err != nil {
-				return "", err
+			valuesScope := scope
+			if inline {
+				valuesScope.args = nil
+			}
+			if valuesScope.args != nil && getDialect(scope) == dialectPostgres {
+				if array, ok := newPostgresArray(values); ok {
+					if e.priority > 11 {
+						exprSql = "(" + exprSql + ")"
+					}
+					return exprSql + arrayOperator + scope.args.add(dialectPostgres, array) + ")", nil
+				}
 			}
+			valuesSql, err = commaValues(valuesScope, values)
 		}
-
-		exprSql, err := e.GetSQL(scope)
 		if err != nil {
 			return "", err
 		}
@@ -758,6 +1588,19 @@ func (e expression) getBuilder(single booleanFunc, joiner joinerFunc, values ...
 	}
 }
 
+// inlineValue wraps the value so that it is inlined even in a parameterized statement.
+func inlineValue(value interface{}) expression {
+	var priority priority
+	if expr, ok := value.(Expression); ok {
+		priority = expr.getOperatorPriority()
+	}
+	return expression{builder: func(scope scope) (string, error) {
+		scope.args = nil
+		sql, _, err := getSQL(scope, value)
+		return sql, err
+	}, priority: priority}
+}
+
 func (e expression) Between(min interface{}, max interface{}) BooleanExpression {
 	return e.buildBetween(" BETWEEN ", min, max)
 }
@@ -772,14 +1615,24 @@ func (e expression) buildBetween(operator string, min interface{}, max interface
 		if err != nil {
 			return "", err
 		}
-		minSql, _, err := getSQL(scope, min)
+		if e.priority > 12 {
+			exprSql = "(" + exprSql + ")"
+		}
+		minSql, minPriority, err := getSQL(scope, min)
 		if err != nil {
 			return "", err
 		}
-		maxSql, _, err := getSQL(scope, max)
+		maxSql, maxPriority, err := getSQL(scope, max)
 		if err != nil {
 			return "", err
 		}
+		// a bound of the same or lower priority, e.g. AND or another BETWEEN, would be ambiguous
+		if minPriority >= 12 {
+			minSql = "(" + minSql + ")"
+		}
+		if maxPriority >= 12 {
+			maxSql = "(" + maxSql + ")"
+		}
 		return exprSql + operator + minSql + " AND " + maxSql, nil
 	}, priority: 12}
 }
@@ -788,6 +1641,15 @@ func (e expression) getOperatorPriority() priority {
 	return e.priority
 }
 
-func (e expression) Desc() OrderBy {
+func (e expression) getColumn() *column {
+	return nil
+}
+
+// Asc creates an ascending order by the expression, which is the default order but allows NULLS FIRST/LAST.
+func (e expression) Asc() SortOrder {
+	return orderBy{by: e}
+}
+
+func (e expression) Desc() SortOrder {
 	return orderBy{by: e, desc: true}
 }
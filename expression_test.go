@@ -1,8 +1,12 @@
 package sqlingo
 
 import (
+	"database/sql"
+	"database/sql/driver"
 	"errors"
+	"math/big"
 	"testing"
+	"time"
 )
 
 type CustomInt int
@@ -59,6 +63,135 @@ func TestExpression(t *testing.T) {
 	assertValue(t, deepNil, "NULL")
 }
 
+type customDecimal struct {
+	s string
+}
+
+func (d customDecimal) String() string {
+	return d.s
+}
+
+// customValuerDecimal is like shopspring decimal.Decimal, a stringer and a valuer of the string.
+type customValuerDecimal struct {
+	s string
+}
+
+func (d customValuerDecimal) String() string {
+	return d.s
+}
+
+func (d customValuerDecimal) Value() (driver.Value, error) {
+	return d.s, nil
+}
+
+// sqlDecimal is a decimal type rendering itself as a numeric literal.
+type sqlDecimal struct {
+	customValuerDecimal
+}
+
+func (d sqlDecimal) SQLValue() string {
+	return d.s
+}
+
+type customLiteral struct{}
+
+func (customLiteral) SQLValue() string {
+	return "CURRENT_DATE"
+}
+
+type customPointerLiteral struct {
+	sql string
+}
+
+func (l *customPointerLiteral) SQLValue() string {
+	return l.sql
+}
+
+func TestNumericValue(t *testing.T) {
+	n, _ := new(big.Int).SetString("-123456789012345678901234567890", 10)
+	assertValue(t, n, "-123456789012345678901234567890")
+	assertValue(t, *n, "-123456789012345678901234567890")
+	assertValue(t, (*big.Int)(nil), "NULL")
+	assertValue(t, big.NewRat(1234, 100), "12.34")
+	assertValue(t, big.NewRat(-1, 8), "-0.125")
+	assertValue(t, big.NewRat(6, 3), "2")
+	assertValue(t, big.NewFloat(2.5), "2.5")
+	if _, _, err := getSQL(dummyMySQLScope, big.NewRat(1, 3)); err == nil {
+		t.Error("should get error here")
+	}
+
+	assertValue(t, customLiteral{}, "CURRENT_DATE")
+	assertValue(t, field1.Equals(customLiteral{}), "`table1`.`field1` = CURRENT_DATE")
+	assertValue(t, &customPointerLiteral{"CURRENT_TIME"}, "CURRENT_TIME")
+	assertValue(t, (*customPointerLiteral)(nil), "NULL")
+
+	// a stringer is quoted, and a valuer is rendered as its value, unless it implements SQLValuer
+	assertValue(t, customDecimal{"12.34"}, "'12.34'")
+	assertValue(t, customValuerDecimal{"12.34"}, "'12.34'")
+	assertValue(t, (*customValuerDecimal)(nil), "NULL")
+	assertValue(t, sqlDecimal{customValuerDecimal{"12.34"}}, "12.34")
+	assertValue(t, &sqlDecimal{customValuerDecimal{"-1.5"}}, "-1.5")
+	assertValue(t, (*sqlDecimal)(nil), "NULL")
+
+	db := newMockDatabase()
+	assertSQLWithArgs(t, db.SelectFrom(table1).Where(field1.Equals(n), field2.GreaterThan(customLiteral{}), field2.LessThan(sqlDecimal{customValuerDecimal{"0.5"}}),
+		field1.NotEquals(customValuerDecimal{"2"})),
+		"SELECT * FROM `table1` WHERE `field1` = -123456789012345678901234567890 AND `field2` > CURRENT_DATE AND `field2` < 0.5 AND `field1` <> ?",
+		customValuerDecimal{"2"})
+}
+
+func TestBinaryValue(t *testing.T) {
+	assertValue(t, []byte{0xde, 0xad, 0xbe, 0xef}, "X'deadbeef'")
+	assertValue(t, []byte{}, "X''")
+	assertValue(t, []byte(nil), "NULL")
+	assertValue(t, field1.Equals([]byte("ab")), "`table1`.`field1` = X'6162'")
+
+	for _, test := range []struct {
+		dialect dialect
+		sql     string
+	}{
+		{dialectUnknown, "X'00ff10'"},
+		{dialectSqlite3, "X'00ff10'"},
+		{dialectPostgres, `'\x00ff10'`},
+		{dialectMSSQL, "0x00ff10"},
+	} {
+		sql, _, err := getSQL(scope{Database: &database{dialect: test.dialect}}, []byte{0, 0xff, 0x10})
+		if err != nil || sql != test.sql {
+			t.Error(test.dialect, sql, err)
+		}
+	}
+
+	db := newMockDatabase()
+	assertSQLWithArgs(t, db.SelectFrom(table1).Where(field1.Equals([]byte("ab"))),
+		"SELECT * FROM `table1` WHERE `field1` = ?", []byte("ab"))
+}
+
+type customValuer struct {
+	err error
+}
+
+func (v customValuer) Value() (driver.Value, error) {
+	return "custom", v.err
+}
+
+func TestValuerValue(t *testing.T) {
+	assertValue(t, sql.NullString{String: "abc", Valid: true}, "'abc'")
+	assertValue(t, sql.NullString{}, "NULL")
+	assertValue(t, sql.NullInt64{Int64: 42, Valid: true}, "42")
+	assertValue(t, sql.NullInt64{}, "NULL")
+	assertValue(t, sql.NullBool{Bool: true, Valid: true}, "1")
+	assertValue(t, sql.NullFloat64{Float64: 1.5, Valid: true}, "1.5")
+	assertValue(t, &sql.NullInt64{Int64: 7, Valid: true}, "7")
+	assertValue(t, (*sql.NullInt64)(nil), "NULL")
+	assertValue(t, customValuer{}, "'custom'")
+	assertValue(t, field1.Equals(sql.NullInt64{Int64: 1, Valid: true}), "`table1`.`field1` = 1")
+	assertError(t, customValuer{err: errors.New("error")})
+
+	db := newMockDatabase()
+	assertSQLWithArgs(t, db.InsertInto(Test).Fields(Test.F1, Test.F2).Values(sql.NullInt64{Int64: 1, Valid: true}, sql.NullString{}),
+		"INSERT INTO `test` (`f1`, `f2`) VALUES (?, NULL)", sql.NullInt64{Int64: 1, Valid: true})
+}
+
 func TestFunc(t *testing.T) {
 	e := expression{
 		builder: func(scope scope) (string, error) {
@@ -83,12 +216,24 @@ func TestFunc(t *testing.T) {
 	assertValue(t, e.Div(e), "<> / <>")
 	assertValue(t, e.IntDiv(e), "<> DIV <>")
 	assertValue(t, e.Mod(e), "<> % <>")
+	assertValue(t, e.Count(), "COUNT(<>)")
+	assertValue(t, e.CountDistinct(), "COUNT(DISTINCT <>)")
 	assertValue(t, e.Sum(), "SUM(<>)")
 	assertValue(t, e.Avg(), "AVG(<>)")
 	assertValue(t, e.Min(), "MIN(<>)")
 	assertValue(t, e.Max(), "MAX(<>)")
+	assertValue(t, e.Round(nil), "ROUND(<>)")
+	assertValue(t, e.Round(2), "ROUND(<>, 2)")
+	assertValue(t, e.Mul(e).Round(2), "ROUND(<> * <>, 2)")
+	assertValue(t, e.Ceil(), "CEILING(<>)")
+	assertValue(t, e.Floor(), "FLOOR(<>)")
+	assertValue(t, e.Abs(), "ABS(<>)")
 	assertValue(t, e.Between(2, 4), "<> BETWEEN 2 AND 4")
 	assertValue(t, e.NotBetween(2, 4), "<> NOT BETWEEN 2 AND 4")
+	assertValue(t, e.Between(e.Or(e), e.Add(1)), "<> BETWEEN (<> OR <>) AND <> + 1")
+	assertValue(t, e.Between(1, e.And(e)), "<> BETWEEN 1 AND (<> AND <>)")
+	assertValue(t, e.NotBetween(e.Between(1, 2), 3), "<> NOT BETWEEN (<> BETWEEN 1 AND 2) AND 3")
+	assertValue(t, e.Or(e).Between(1, 2), "(<> OR <>) BETWEEN 1 AND 2")
 
 	assertValue(t, e.In(), "FALSE")
 	assertValue(t, e.In(1), "<> = 1")
@@ -105,9 +250,62 @@ func TestFunc(t *testing.T) {
 	assertValue(t, e.NotIn([]int64{1}), "<> <> 1")
 	assertValue(t, e.NotIn([]int64{1, 2, 3}), "<> NOT IN (1, 2, 3)")
 
+	var nilPointer *int
+	assertValue(t, e.In(1, nil, 2), "<> IS NULL OR <> IN (1, 2)")
+	assertValue(t, e.In(1, nil), "<> IS NULL OR <> = 1")
+	assertValue(t, e.In(nil), "<> IS NULL")
+	assertValue(t, e.In([]interface{}{1, nil}), "<> IS NULL OR <> = 1")
+	assertValue(t, e.In(nilPointer, Null(), 3), "<> IS NULL OR <> = 3")
+	assertValue(t, e.NotIn(1, nil, 2), "<> IS NOT NULL AND <> NOT IN (1, 2)")
+	assertValue(t, e.NotIn(nil), "<> IS NOT NULL")
+	assertValue(t, e.In(1, nil, 2).Not(), "NOT (<> IS NULL OR <> IN (1, 2))")
+	assertValue(t, e.In(1, nil, 2).And(e), "(<> IS NULL OR <> IN (1, 2)) AND <>")
+
 	assertValue(t, e.Like("%A%"), "<> LIKE '%A%'")
+	assertValue(t, e.NotLike("%A%"), "<> NOT LIKE '%A%'")
+	assertValue(t, e.ILike("%A%"), "LOWER(<>) LIKE LOWER('%A%')")
+	assertValue(t, e.ILike("%A%").Not(), "NOT LOWER(<>) LIKE LOWER('%A%')")
+	assertDialectValue(t, dialectPostgres, e.ILike("%A%"), "<> ILIKE '%A%'")
+	assertValue(t, e.BitAnd(4), "<> & 4")
+	assertValue(t, e.BitAnd(4).NotEquals(0), "<> & 4 <> 0")
+	assertValue(t, e.BitOr(1).BitAnd(2), "(<> | 1) & 2")
+	assertValue(t, e.BitAnd(1).BitOr(2), "<> & 1 | 2")
+	assertValue(t, e.ShiftLeft(2).Add(1), "(<> << 2) + 1")
+	assertValue(t, e.Add(1).ShiftRight(2), "<> + 1 >> 2")
+	assertValue(t, e.BitXor(3), "<> ^ 3")
+	assertDialectValue(t, dialectPostgres, e.BitXor(3), "<> # 3")
+	assertDialectValue(t, dialectSqlite3, e.BitXor(3), "(<> | 3) - (<> & 3)")
+	assertValue(t, e.BitXor(3).Mul(2), "(<> ^ 3) * 2")
+
+	assertValue(t, e.Negate(), "-<>")
+	assertValue(t, e.Add(1).Negate(), "-(<> + 1)")
+	assertValue(t, e.Negate().Mul(2), "-<> * 2")
+	assertValue(t, e.Negate().Negate(), "-(-<>)")
+	assertValue(t, Raw("a + b").Negate(), "-(a + b)")
+	assertValue(t, Neg(5), "-5")
+	assertValue(t, Neg(-5), "-(-5)")
+	assertValue(t, Neg(e.Sub(1)), "-(<> - 1)")
+	assertValue(t, e.Sub(Neg(1)), "<> - -1")
+	assertValue(t, Raw("a OR b").Not(), "NOT (a OR b)")
+
+	assertValue(t, e.LikeEscape(`a!_b%`, "!"), "<> LIKE 'a!_b%' ESCAPE '!'")
+	assertValue(t, e.LikeEscape(`a\_b`, `\`), `<> LIKE 'a\\_b' ESCAPE '\\'`)
+	assertValue(t, e.LikeEscape("a%", "!").Not(), "NOT <> LIKE 'a%' ESCAPE '!'")
+	assertDialectValue(t, dialectPostgres, e.LikeEscape("a", `\`), `<> LIKE 'a' ESCAPE '\'`)
+	assertDialectValue(t, dialectSqlite3, e.LikeEscape("a", "'"), `<> LIKE 'a' ESCAPE ''''`)
+	assertError(t, e.LikeEscape("a", ""))
+	assertError(t, e.LikeEscape("a", "!!"))
+	assertValue(t, e.Regexp("^a+$"), "<> REGEXP '^a+$'")
+	assertDialectValue(t, dialectPostgres, e.Regexp("^a+$"), "<> ~ '^a+$'")
+	assertDialectError(t, dialectMSSQL, e.Regexp("^a+$"))
 	assertValue(t, e.Concat("-suffix"), "CONCAT(<>, '-suffix')")
 	assertValue(t, e.Contains("\n"), "LOCATE('\\\n', <>) > 0")
+	assertValue(t, e.Position("@"), "LOCATE('@', <>)")
+	assertValue(t, e.Substring(e.Position("@").Add(1), nil), "SUBSTRING(<>, LOCATE('@', <>) + 1)")
+	assertDialectValue(t, dialectPostgres, e.Position(e.Lower()), "POSITION(LOWER(<>) IN <>)")
+	assertDialectValue(t, dialectPostgres, e.Contains("x"), "POSITION('x' IN <>) > 0")
+	assertDialectValue(t, dialectSqlite3, e.Position("x"), "INSTR(<>, 'x')")
+	assertDialectValue(t, dialectMSSQL, e.Position("x"), "CHARINDEX('x', <>)")
 
 	assertValue(t, []interface{}{1, 2, 3, "d"}, "(1, 2, 3, 'd')")
 
@@ -123,9 +321,48 @@ func TestFunc(t *testing.T) {
 	assertValue(t, e.IsEmpty(), "<> = ''")
 	assertValue(t, e.Lower(), "LOWER(<>)")
 	assertValue(t, e.Upper(), "UPPER(<>)")
+	assertValue(t, e.Reverse(), "REVERSE(<>)")
+	assertDialectValue(t, dialectMSSQL, e.Reverse(), "REVERSE(<>)")
+	assertDialectError(t, dialectSqlite3, e.Reverse())
+	assertValue(t, e.Collate("utf8mb4_bin"), "<> COLLATE `utf8mb4_bin`")
+	assertValue(t, e.Collate("utf8mb4_bin").Equals("X"), "<> COLLATE `utf8mb4_bin` = 'X'")
+	assertValue(t, e.Add(1).Collate("C"), "(<> + 1) COLLATE `C`")
+	assertValue(t, e.Lower().Collate("C"), "LOWER(<>) COLLATE `C`")
+	assertDialectValue(t, dialectPostgres, e.Collate("en_US.utf8"), `<> COLLATE "en_US.utf8"`)
+	assertDialectValue(t, dialectPostgres, e.Add(1).Collate("und-x-icu"), `(<> + 1) COLLATE "und-x-icu"`)
+	assertDialectValue(t, dialectMSSQL, e.Collate("Latin1_General_CS_AS"), "<> COLLATE Latin1_General_CS_AS")
+	assertDialectError(t, dialectMSSQL, e.Collate("en_US.utf8"))
+	assertError(t, e.Collate("utf8mb4_bin; DROP TABLE t"))
+	assertError(t, e.Collate("a`b"))
+	assertError(t, e.Collate(""))
+	assertValue(t, e.Lower().Reverse().Left(3), "LEFT(REVERSE(LOWER(<>)), 3)")
 	assertValue(t, e.Left(10), "LEFT(<>, 10)")
 	assertValue(t, e.Right(10), "RIGHT(<>, 10)")
 	assertValue(t, e.Trim(), "TRIM(<>)")
+	assertValue(t, e.Ltrim(), "LTRIM(<>)")
+	assertValue(t, e.Rtrim(), "RTRIM(<>)")
+	assertValue(t, e.TrimChars(`"`), `TRIM('\"' FROM <>)`)
+	assertDialectValue(t, dialectPostgres, e.TrimChars("xy"), "TRIM('xy' FROM <>)")
+	assertDialectValue(t, dialectSqlite3, e.Lower().TrimChars("x"), "TRIM(LOWER(<>), 'x')")
+	assertValue(t, e.Substring(2, nil), "SUBSTRING(<>, 2)")
+	assertValue(t, e.Replace("-", ""), "REPLACE(<>, '-', '')")
+	assertValue(t, e.LPad(5), "LPAD(<>, 5, ' ')")
+	assertValue(t, e.LPad(5, "0"), "LPAD(<>, 5, '0')")
+	assertValue(t, e.RPad(e.Add(1), e), "RPAD(<>, <> + 1, <>)")
+	assertDialectValue(t, dialectPostgres, e.RPad(5, "."), "RPAD(<>, 5, '.')")
+	assertDialectError(t, dialectMSSQL, e.LPad(5))
+	assertDialectError(t, dialectSqlite3, e.RPad(5))
+	assertValue(t, e.Repeat(3), "REPEAT(<>, 3)")
+	assertDialectValue(t, dialectMSSQL, e.Repeat(3), "REPLICATE(<>, 3)")
+	assertDialectError(t, dialectSqlite3, e.Repeat(3))
+	assertValue(t, e.GroupConcat(""), "GROUP_CONCAT(<>)")
+	assertValue(t, e.GroupConcat("'; "), "GROUP_CONCAT(<> SEPARATOR '\\'; ')")
+	assertDialectValue(t, dialectPostgres, e.GroupConcat(""), "STRING_AGG(<>, ',')")
+	assertDialectValue(t, dialectMSSQL, e.GroupConcat("|"), "STRING_AGG(<>, '|')")
+	assertDialectValue(t, dialectSqlite3, e.GroupConcat("|"), "GROUP_CONCAT(<>, '|')")
+	assertValue(t, e.Replace(e, "x").Trim(), "TRIM(REPLACE(<>, <>, 'x'))")
+	assertValue(t, e.Substring(2, 3), "SUBSTRING(<>, 2, 3)")
+	assertValue(t, e.Substring(e.Add(1), e), "SUBSTRING(<>, <> + 1, <>)")
 	assertValue(t, e.HasPrefix("abc"), "LEFT(<>, CHAR_LENGTH('abc')) = 'abc'")
 	assertValue(t, e.HasSuffix("abc"), "RIGHT(<>, CHAR_LENGTH('abc')) = 'abc'")
 
@@ -195,6 +432,10 @@ func TestLogicalExpression(t *testing.T) {
 
 	assertValue(t, And(), "TRUE")
 	assertValue(t, Or(), "FALSE")
+	assertValue(t, And(a), "a")
+	assertValue(t, Or(a), "a")
+	assertValue(t, And(a.Or(b)), "a OR b")
+	assertValue(t, Or(a.And(b), c), "a AND b OR c")
 }
 
 func TestLogicalOptimizer(t *testing.T) {
@@ -227,4 +468,86 @@ func TestLogicalOptimizer(t *testing.T) {
 
 	assertValue(t, trueValue.And(otherBoolValue), "<>")
 	assertValue(t, falseValue.Or(otherBoolValue), "<>")
+
+	assertValue(t, Null().IsNull(), "TRUE")
+	assertValue(t, Null().IsNotNull(), "FALSE")
+	assertValue(t, Value(nil).IsNull(), "TRUE")
+	assertValue(t, Value((*int)(nil)).IsNull(), "TRUE")
+	assertValue(t, Value("x").IsNull(), "FALSE")
+	assertValue(t, Value(1).IsNotNull(), "TRUE")
+	assertValue(t, trueValue.IsNull(), "FALSE")
+	assertValue(t, otherValue.IsNull(), "<> IS NULL")
+	assertValue(t, Value("x").IsNull().Or(otherBoolValue), "<>")
+	assertValue(t, Value("x").Equals(Null()), "'x' = NULL")
+	assertValue(t, Value(time.Time{}).IsNull(), "TRUE")
+}
+
+func TestAliasExpression(t *testing.T) {
+	db := newMockDatabase()
+	price := expression{sql: "price"}
+	qty := expression{sql: "qty"}
+	total := price.Mul(qty).As("total")
+
+	// referenced by the name by default
+	assertValue(t, total, "total")
+	assertValue(t, price.Add(total), "price + total")
+	_, _ = db.Select(total).From(table1).GroupBy(price).Having(qty.GreaterThan(total)).OrderBy(total).FetchAll()
+	assertLastSql(t, "SELECT price * qty AS total FROM `table1` GROUP BY price HAVING qty > total ORDER BY total")
+
+	db.EnableAliasInlining(true)
+	inliningScope := scope{Database: &database{dialect: dialectMySQL, aliasInlining: true}}
+	for _, test := range []struct {
+		value interface{}
+		sql   string
+	}{
+		{total, "price * qty"},
+		{price.Add(total), "price + price * qty"},
+		{expression{sql: "x"}.Mul(price.Add(qty).As("s")), "x * (price + qty)"},
+	} {
+		if sql, _, err := getSQL(inliningScope, test.value); err != nil || sql != test.sql {
+			t.Error(sql, err, test.sql)
+		}
+	}
+
+	_, _ = db.Select(total, qty.Mul(total).As("double_total")).From(table1).
+		Where(price.LessThan(total)).
+		OrderBy(total).
+		FetchAll()
+	assertLastSql(t, "SELECT price * qty AS total, qty * (price * qty) AS double_total FROM `table1` "+
+		"WHERE price < price * qty ORDER BY price * qty")
+}
+
+func TestExists(t *testing.T) {
+	db := newMockDatabase()
+	subquery := db.Select(1).From(table2).Where(field3.Equals(field1))
+
+	assertValue(t, Exists(subquery), "EXISTS (SELECT 1 FROM `table2` WHERE `field3` = `table1`.`field1`)")
+	assertValue(t, NotExists(subquery), "NOT EXISTS (SELECT 1 FROM `table2` WHERE `field3` = `table1`.`field1`)")
+
+	_, _ = db.SelectFrom(table1).Where(field1.Equals(1).And(Exists(subquery)), NotExists(subquery).Or(field2.IsNull())).FetchAll()
+	assertLastSql(t, "SELECT * FROM `table1` WHERE `field1` = 1 AND EXISTS (SELECT 1 FROM `table2` WHERE `field3` = `table1`.`field1`) AND "+
+		"(NOT EXISTS (SELECT 1 FROM `table2` WHERE `field3` = `table1`.`field1`) OR `field2` IS NULL)")
+	assertValue(t, Exists(subquery).Not(), "NOT EXISTS (SELECT 1 FROM `table2` WHERE `field3` = `table1`.`field1`)")
+}
+
+func TestRawWithArgs(t *testing.T) {
+	assertValue(t, Raw("a = ? AND b IN ?", "x'y", []int{1, 2}), `a = 'x\'y' AND b IN (1, 2)`)
+	assertValue(t, Raw("a = '?' AND b = \"\\\"?\" AND c = ?", 1), "a = '?' AND b = \"\\\"?\" AND c = 1")
+	assertValue(t, Raw("?"), "?")
+	assertError(t, Raw("a = ? AND b = ?", 1))
+	assertError(t, Raw("a = ?", 1, 2))
+
+	db := newMockDatabase()
+	_, _ = db.SelectFrom(table1).Where(field1.Equals(1)).WhereRaw("jsonb_col @> ?::jsonb", `{"a":1}`).FetchAll()
+	assertLastSql(t, "SELECT * FROM `table1` WHERE `field1` = 1 AND (jsonb_col @> '{\\\"a\\\":1}'::jsonb)")
+	_, _ = db.Update(table1).Set(field1, 1).WhereRaw("field2 & ? = 0", 4).Execute()
+	assertLastSql(t, "UPDATE `table1` SET `field1` = 1 WHERE field2 & 4 = 0")
+	_, _ = db.DeleteFrom(table1).WhereRaw("field2 = ?", "x").Execute()
+	assertLastSql(t, "DELETE FROM `table1` WHERE field2 = 'x'")
+
+	sqlString, args, _ := db.DeleteFrom(table1).WhereRaw("field2 = ?", "x").GetSQLWithArgs()
+	assertEqual(t, sqlString, "DELETE FROM `table1` WHERE field2 = ?")
+	if len(args) != 1 || args[0] != "x" {
+		t.Error(args)
+	}
 }
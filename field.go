@@ -1,6 +1,9 @@
 package sqlingo
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
 // Field is the interface of a generated field.
 type Field interface {
@@ -38,18 +41,42 @@ type DateField interface {
 	GetTable() Table
 }
 
+// columnKind is the kind of values of a column, as declared by the generated code.
+type columnKind uint8
+
+const (
+	columnUnknown columnKind = iota
+	columnNumber
+	columnBoolean
+	columnString
+	columnDate
+	columnBinary
+)
+
+// column is the metadata of the column of a field.
+type column struct {
+	name string
+	kind columnKind
+}
+
 type actualField struct {
 	expression
-	table Table
+	table  Table
+	column column
 }
 
 func (f actualField) GetTable() Table {
 	return f.table
 }
 
-func newField(table Table, fieldName string) actualField {
+func (f actualField) getColumn() *column {
+	return &f.column
+}
+
+// newField creates a reference to a column of the table. It is a pointer so that fields can be map keys.
+func newField(table Table, fieldName string, kind columnKind) *actualField {
 	tableName := table.GetName()
-	tableNameSqlArray := quoteIdentifier(tableName)
+	tableNameSqlArray := getTableSQLArray(table)
 	fieldNameSqlArray := quoteIdentifier(fieldName)
 
 	var fullFieldNameSqlArray dialectArray
@@ -57,7 +84,7 @@ func newField(table Table, fieldName string) actualField {
 		fullFieldNameSqlArray[dialect] = tableNameSqlArray[dialect] + "." + fieldNameSqlArray[dialect]
 	}
 
-	return actualField{
+	return &actualField{
 		expression: expression{
 			builder: func(scope scope) (string, error) {
 				dialect := dialectUnknown
@@ -70,28 +97,48 @@ func newField(table Table, fieldName string) actualField {
 				return fieldNameSqlArray[dialect], nil
 			},
 		},
-		table: table,
+		table:  table,
+		column: column{name: fieldName, kind: kind},
 	}
 }
 
 // NewNumberField creates a reference to a number field. It should only be called from generated code.
 func NewNumberField(table Table, fieldName string) NumberField {
-	return newField(table, fieldName)
+	return newField(table, fieldName, columnNumber)
 }
 
 // NewBooleanField creates a reference to a boolean field. It should only be called from generated code.
 func NewBooleanField(table Table, fieldName string) BooleanField {
-	return newField(table, fieldName)
+	return newField(table, fieldName, columnBoolean)
 }
 
 // NewStringField creates a reference to a string field. It should only be called from generated code.
 func NewStringField(table Table, fieldName string) StringField {
-	return newField(table, fieldName)
+	return newField(table, fieldName, columnString)
 }
 
 // NewDateField creates a reference to a time.Time field. It should only be called from generated code.
 func NewDateField(table Table, fieldName string) DateField {
-	return newField(table, fieldName)
+	return newField(table, fieldName, columnDate)
+}
+
+// QualifiedName returns the quoted, table-qualified name of the field in the dialect of the database,
+// e.g. `table`.`column` on MySQL, for splicing into raw SQL fragments.
+func QualifiedName(db Database, field Field) (string, error) {
+	scope := scope{Database: &database{}}
+	if d, ok := db.(*database); ok {
+		scope.Database = d
+	}
+	// an empty table list forces the qualification
+	return field.GetSQL(scope)
+}
+
+// getFieldName returns the column name of a field of a table.
+func getFieldName(field Field) (string, error) {
+	if column := field.getColumn(); column != nil {
+		return column.name, nil
+	}
+	return "", fmt.Errorf("%T is not a column of a table", field)
 }
 
 type fieldList []Field
@@ -124,3 +171,46 @@ func (fields fieldList) GetSQL(scope scope) (string, error) {
 	}
 	return sb.String(), nil
 }
+
+// aliasDuplicates aliases the fields of different tables with the same column name as table_column,
+// so that the columns of the result set are named uniquely.
+func (fields fieldList) aliasDuplicates() fieldList {
+	names := make([]string, len(fields))
+	tableNamesByName := make(map[string]map[string]bool)
+	for i, field := range fields {
+		table := field.GetTable()
+		if table == nil {
+			continue
+		}
+		name, err := getFieldName(field)
+		if err != nil {
+			continue
+		}
+		names[i] = name
+		if tableNamesByName[name] == nil {
+			tableNamesByName[name] = make(map[string]bool)
+		}
+		tableNamesByName[name][table.GetName()] = true
+	}
+
+	var result fieldList
+	for i, field := range fields {
+		if names[i] == "" || len(tableNamesByName[names[i]]) < 2 {
+			continue
+		}
+		if result == nil {
+			result = make(fieldList, len(fields))
+			copy(result, fields)
+		}
+		alias := field.GetTable().GetName() + "_" + names[i]
+		if nullable, ok := field.(nullableExpression); ok {
+			result[i] = nullable.alias(nullable.wrapped.As(alias).(aliasExpression).quoted())
+		} else {
+			result[i] = field.As(alias).(aliasExpression).quoted()
+		}
+	}
+	if result == nil {
+		return fields
+	}
+	return result
+}
@@ -70,3 +70,41 @@ func TestField(t *testing.T) {
 		t.Error("should get error here")
 	}
 }
+
+func TestFieldAsOperand(t *testing.T) {
+	db := newMockDatabase()
+	t1 := NewTable("t1")
+	t2 := NewTable("t2")
+	x := NewNumberField(t1, "x")
+	y := NewNumberField(t2, "y")
+	name := NewStringField(t2, "name")
+
+	assertValue(t, x.Equals(y), "`t1`.`x` = `t2`.`y`")
+	assertValue(t, x.LessThan(y.Add(1)), "`t1`.`x` < `t2`.`y` + 1")
+	assertValue(t, name.Like(name), "`t2`.`name` LIKE `t2`.`name`")
+
+	_, _ = db.Select(x, y).From(t1).Join(t2).On(x.Equals(y)).Where(y.NotEquals(x)).FetchAll()
+	assertLastSql(t, "SELECT `t1`.`x`, `t2`.`y` FROM `t1` JOIN `t2` ON `t1`.`x` = `t2`.`y` WHERE `t2`.`y` <> `t1`.`x`")
+
+	db.(*database).dialect = dialectPostgres
+	_, _ = db.SelectFrom(t1, t2).Where(x.Equals(y)).FetchAll()
+	assertLastSql(t, `SELECT *, * FROM "t1", "t2" WHERE "t1"."x" = "t2"."y"`)
+}
+
+func TestQualifiedName(t *testing.T) {
+	db := newMockDatabase()
+	t1 := NewTable("t1")
+	f1 := NewNumberField(t1, "f1")
+
+	if name, _ := QualifiedName(db, f1); name != "`t1`.`f1`" {
+		t.Error(name)
+	}
+	db.(*database).dialect = dialectPostgres
+	if name, _ := QualifiedName(db, f1); name != `"t1"."f1"` {
+		t.Error(name)
+	}
+	db.(*database).dialect = dialectMSSQL
+	if name, _ := QualifiedName(db, f1); name != "[t1].[f1]" {
+		t.Error(name)
+	}
+}
@@ -0,0 +1,134 @@
+package sqlingo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// Fingerprint returns a stable hash of the query, which can be used as a key of a result cache.
+// Comments and redundant whitespace outside literals are ignored, so logically identical queries share a key.
+func (s selectStatus) Fingerprint() (string, error) {
+	sqlString, err := s.GetSQL()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(normalizeSQL(sqlString)))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// normalizeSQL removes block comments and collapses whitespace outside quoted literals and identifiers.
+func normalizeSQL(sql string) string {
+	var sb strings.Builder
+	sb.Grow(len(sql))
+	var quote byte
+	pendingSpace := false
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		if quote != 0 {
+			sb.WriteByte(c)
+			switch {
+			case c == '\\' && quote == '\'' && i+1 < len(sql):
+				i++
+				sb.WriteByte(sql[i])
+			case c == quote:
+				quote = 0
+			}
+			continue
+		}
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			pendingSpace = true
+			continue
+		case c == '/' && i+1 < len(sql) && sql[i+1] == '*':
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				i = len(sql)
+			} else {
+				i += end + 3
+			}
+			pendingSpace = true
+			continue
+		case c == '\'' || c == '"' || c == '`' || c == '[':
+			quote = c
+			if c == '[' {
+				quote = ']'
+			}
+		}
+		if pendingSpace && sb.Len() > 0 {
+			sb.WriteByte(' ')
+		}
+		pendingSpace = false
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+// normalizeSpaces collapses the runs of spaces and trims the spaces at both ends, inside parentheses and
+// before commas, e.g. those from raw fragments, so that the generated SQL is spaced canonically.
+// Unlike normalizeSQL, it keeps the comments and line breaks, and leaves the literals, quoted identifiers,
+// comments and dollar-quoted strings as they are. A backslash escapes a quote in the string literals only on MySQL,
+// as the other dialects take it literally, e.g. 'C:\' on PostgreSQL.
+func normalizeSpaces(sql string, dialect dialect) string {
+	if !strings.Contains(sql, "  ") && !strings.Contains(sql, "( ") && !strings.Contains(sql, " )") &&
+		!strings.Contains(sql, " ,") && !strings.HasPrefix(sql, " ") && !strings.HasSuffix(sql, " ") {
+		return sql
+	}
+	var sb strings.Builder
+	sb.Grow(len(sql))
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		end := i
+		switch {
+		case c == ' ':
+			for i+1 < len(sql) && sql[i+1] == ' ' {
+				i++
+			}
+			// no space at both ends, after an opening parenthesis, or before a closing one or a comma
+			written := sb.String()
+			if len(written) > 0 && written[len(written)-1] != '(' && i+1 < len(sql) && sql[i+1] != ')' && sql[i+1] != ',' {
+				sb.WriteByte(' ')
+			}
+			continue
+		case c == '\'' || c == '"' || c == '`' || c == '[':
+			closing := c
+			if c == '[' {
+				closing = ']'
+			}
+			for end = i + 1; end < len(sql) && sql[end] != closing; end++ {
+				if sql[end] == '\\' && c == '\'' && (dialect == dialectMySQL || dialect == dialectUnknown) {
+					end++
+				}
+			}
+		case c == '/' && strings.HasPrefix(sql[i:], "/*"):
+			end = len(sql) - 1
+			if n := strings.Index(sql[i+2:], "*/"); n >= 0 {
+				end = i + 2 + n + 1
+			}
+		case c == '-' && strings.HasPrefix(sql[i:], "--"):
+			end = len(sql) - 1
+			if n := strings.IndexByte(sql[i:], '\n'); n >= 0 {
+				end = i + n
+			}
+		case c == '$':
+			// PostgreSQL dollar-quoted string, e.g. $tag$...$tag$, but not a $1 placeholder
+			tagEnd := i + 1
+			for tagEnd < len(sql) && (sql[tagEnd] == '_' || sql[tagEnd] >= 'a' && sql[tagEnd] <= 'z' || sql[tagEnd] >= 'A' && sql[tagEnd] <= 'Z') {
+				tagEnd++
+			}
+			if tagEnd < len(sql) && sql[tagEnd] == '$' {
+				tag := sql[i : tagEnd+1]
+				end = len(sql) - 1
+				if n := strings.Index(sql[tagEnd+1:], tag); n >= 0 {
+					end = tagEnd + n + len(tag)
+				}
+			}
+		}
+		if end >= len(sql) {
+			end = len(sql) - 1
+		}
+		sb.WriteString(sql[i : end+1])
+		i = end
+	}
+	return sb.String()
+}
@@ -0,0 +1,74 @@
+package sqlingo
+
+import "testing"
+
+func TestNormalizeSQL(t *testing.T) {
+	assertEqual(t, normalizeSQL("  SELECT  *\n\tFROM t /* comment */ WHERE a = 1 "), "SELECT * FROM t WHERE a = 1")
+	assertEqual(t, normalizeSQL("/* main.go:10 */ SELECT 'a  b', \"c  d\", `e  f`, [g  h]"),
+		"SELECT 'a  b', \"c  d\", `e  f`, [g  h]")
+	assertEqual(t, normalizeSQL(`SELECT 'it\'s  /* x */'  FROM t`), `SELECT 'it\'s  /* x */' FROM t`)
+}
+
+func TestNormalizeSpaces(t *testing.T) {
+	assertEqual(t, normalizeSpaces("SELECT * FROM t", dialectMySQL), "SELECT * FROM t")
+	assertEqual(t, normalizeSpaces("  SELECT  *   FROM t  ", dialectMySQL), "SELECT * FROM t")
+	assertEqual(t, normalizeSpaces("SELECT 'a  b', \"c  d\", `e  f`, [g  h]  FROM t", dialectMySQL),
+		"SELECT 'a  b', \"c  d\", `e  f`, [g  h] FROM t")
+	assertEqual(t, normalizeSpaces(`SELECT 'it\'s  x'  FROM t`, dialectMySQL), `SELECT 'it\'s  x' FROM t`)
+	assertEqual(t, normalizeSpaces("SELECT /* a  b */  1  -- c  d\n  FROM t", dialectMySQL), "SELECT /* a  b */ 1 -- c  d\n FROM t")
+	assertEqual(t, normalizeSpaces("SELECT $body$a  b$body$,  $1  FROM t", dialectMySQL), "SELECT $body$a  b$body$, $1 FROM t")
+	assertEqual(t, normalizeSpaces("SELECT 'a  ", dialectMySQL), "SELECT 'a  ")
+	assertEqual(t, normalizeSpaces("SELECT f( 1 , 2 )", dialectMySQL), "SELECT f(1, 2)")
+
+	// a backslash ends a literal on the other dialects, so the spaces of the next literal are kept
+	for _, dialect := range []dialect{dialectPostgres, dialectSqlite3, dialectMSSQL} {
+		assertEqual(t, normalizeSpaces(`SELECT 'C:\'  ,  '  x  ( '`, dialect), `SELECT 'C:\', '  x  ( '`)
+	}
+}
+
+func TestCanonicalSpacing(t *testing.T) {
+	db := newMockDatabase()
+
+	_, _ = db.SelectFrom(table1).WhereRaw("  `field1`  =  ? ", "a  b").GroupBy(Raw(" `field2` ")).
+		Having(Raw("COUNT(*)  >  1")).OrderBy(field1).Limit(1).FetchAll()
+	assertLastSql(t, "SELECT * FROM `table1` WHERE `field1` = 'a  b' GROUP BY `field2` HAVING COUNT(*) > 1 ORDER BY `field1` LIMIT 1")
+
+	_, _ = db.Select(field1, Raw(" 1 ")).From(table1).Join(table2).On(Raw("`field1`  = `field3` ")).
+		Where(field1.In(db.Select(field3).From(table2).WhereRaw("  TRUE  "))).FetchAll()
+	assertLastSql(t, "SELECT `table1`.`field1`, 1 FROM `table1` JOIN `table2` ON `field1` = `field3` "+
+		"WHERE `table1`.`field1` IN (SELECT `field3` FROM `table2` WHERE TRUE)")
+
+	_, _ = db.InsertInto(Test).Fields(Test.F1, Test.F2).Values(Raw(" 1 "), "  ").OnDuplicateKeyUpdate().Set(Test.F2, Raw("  'x'")).Execute()
+	assertLastSql(t, "INSERT INTO `test` (`f1`, `f2`) VALUES (1, '  ') ON DUPLICATE KEY UPDATE `f2` = 'x'")
+
+	_, _ = db.Update(Test).Set(Test.F2, Raw("  CONCAT(`f2`,  ' ')")).WhereRaw(" `f1`  >  1 ").Execute()
+	assertLastSql(t, "UPDATE `test` SET `f2` = CONCAT(`f2`, ' ') WHERE `f1` > 1")
+
+	_, _ = db.DeleteFrom(Test).WhereRaw("`f1`  =  1  ").Execute()
+	assertLastSql(t, "DELETE FROM `test` WHERE `f1` = 1")
+
+	db.(*database).dialect = dialectPostgres
+	_, _ = db.SelectFrom(Test).Where(Raw(`"f2" = 'C:\'`), Test.F2.Equals("  x  ( ")).FetchAll()
+	assertLastSql(t, `SELECT * FROM "test" WHERE ("f2" = 'C:\') AND "f2" = '  x  ( '`)
+}
+
+func TestFingerprint(t *testing.T) {
+	db := newMockDatabase()
+
+	a, err := db.SelectFrom(table1).Where(field1.Equals(1)).Fingerprint()
+	if err != nil {
+		t.Error(err)
+	}
+	b, _ := db.SelectFrom(table1).Where(field1.Equals(1)).Fingerprint()
+	c, _ := db.SelectFrom(table1).Where(field1.Equals(2)).Fingerprint()
+	d, _ := db.SelectFrom(table1).Where(Raw("`field1`   =  1")).Fingerprint()
+	if a != b || a != d {
+		t.Error("identical queries should share the fingerprint")
+	}
+	if a == c {
+		t.Error("different queries should not share the fingerprint")
+	}
+	if len(a) != 64 {
+		t.Error(a)
+	}
+}
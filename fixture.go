@@ -0,0 +1,70 @@
+package sqlingo
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+var fixtureTime = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// InsertFixture initiates an INSERT of a single row into the table of the model for test fixtures.
+// The NOT NULL columns are filled with placeholder values chosen by the kinds of the generated fields,
+// e.g. 0 for a NumberField, an empty string for a StringField and 2000-01-01 for a DateField,
+// and the nullable columns, whose model values are pointers, with NULL.
+// The overrides take precedence over the placeholder values.
+func (d *database) InsertFixture(model Model, overrides map[Field]interface{}) insertWithRows {
+	table := model.GetTable()
+	fields := table.GetFields()
+	values := model.GetValues()
+
+	row := make(map[string]interface{}, len(fields))
+	for i, field := range fields {
+		name, err := getFieldName(field)
+		if err != nil {
+			continue
+		}
+		nullable := i < len(values) && reflect.TypeOf(values[i]) != nil && reflect.TypeOf(values[i]).Kind() == reflect.Ptr
+		row[name] = getFixtureValue(field, nullable)
+	}
+	for field, value := range overrides {
+		name, err := getFieldName(field)
+		if err != nil {
+			// the row fails to build with the error
+			row[""] = errorExpression(err)
+			continue
+		}
+		if field.GetTable() == nil || field.GetTable().GetName() != table.GetName() {
+			row[name] = errorExpression(fmt.Errorf("column %s is not in table %s", name, table.GetName()))
+			continue
+		}
+		row[name] = value
+	}
+	return d.InsertInto(table).Rows([]map[string]interface{}{row})
+}
+
+func getFixtureValue(field Field, nullable bool) interface{} {
+	if nullable {
+		return nil
+	}
+	column := field.getColumn()
+	switch column.kind {
+	case columnNumber:
+		return 0
+	case columnBoolean:
+		return false
+	case columnString:
+		return ""
+	case columnDate:
+		return fixtureTime
+	default:
+		return errorExpression(fmt.Errorf("cannot generate a fixture value for column %s", column.name))
+	}
+}
+
+// errorExpression is an expression that fails to build with the error.
+func errorExpression(err error) expression {
+	return expression{builder: func(scope scope) (string, error) {
+		return "", err
+	}}
+}
@@ -0,0 +1,65 @@
+package sqlingo
+
+import (
+	"testing"
+	"time"
+)
+
+type fixtureTable struct {
+	Table
+	ID        NumberField
+	Name      StringField
+	Nickname  StringField
+	CreatedAt DateField
+	Data      StringField
+}
+
+type fixtureModel struct {
+	ID        int64
+	Name      string
+	Nickname  *string
+	CreatedAt time.Time
+	Data      []byte
+}
+
+var fixture = func() fixtureTable {
+	t := fixtureTable{Table: NewTable("fixture")}
+	t.ID = NewNumberField(t, "id")
+	t.Name = NewStringField(t, "name")
+	t.Nickname = NewStringField(t, "nickname")
+	t.CreatedAt = NewDateField(t, "created_at")
+	t.Data = NewStringField(t, "data")
+	return t
+}()
+
+func (t fixtureTable) GetFields() []Field {
+	return []Field{t.ID, t.Name, t.Nickname, t.CreatedAt, t.Data}
+}
+
+func (m fixtureModel) GetTable() Table {
+	return fixture
+}
+
+func (m fixtureModel) GetValues() []interface{} {
+	return []interface{}{m.ID, m.Name, m.Nickname, m.CreatedAt, m.Data}
+}
+
+func TestInsertFixture(t *testing.T) {
+	db := newMockDatabase()
+
+	if _, err := db.InsertFixture(fixtureModel{}, nil).Execute(); err != nil {
+		t.Error(err)
+	}
+	assertLastSql(t, "INSERT INTO `fixture` (`created_at`, `data`, `id`, `name`, `nickname`) "+
+		"VALUES ('2000-01-01 00:00:00.000000', '', 0, '', NULL)")
+
+	if _, err := db.InsertFixture(fixtureModel{}, map[Field]interface{}{fixture.Name: "alice", fixture.ID: 42}).Execute(); err != nil {
+		t.Error(err)
+	}
+	assertLastSql(t, "INSERT INTO `fixture` (`created_at`, `data`, `id`, `name`, `nickname`) "+
+		"VALUES ('2000-01-01 00:00:00.000000', '', 42, 'alice', NULL)")
+
+	if _, err := db.InsertFixture(fixtureModel{}, map[Field]interface{}{field1: 1}).Execute(); err == nil {
+		t.Error("should fail with a column of another table")
+	}
+}
@@ -1,12 +1,24 @@
 package sqlingo
 
 func function(name string, args ...interface{}) expression {
+	isDistinctAggregate := false
+	for _, arg := range args {
+		if e, ok := arg.(expression); ok && e.isDistinct {
+			isDistinctAggregate = true
+		}
+	}
 	return expression{builder: func(scope scope) (string, error) {
 		valuesSql, err := commaValues(scope, args)
 		if err != nil {
 			return "", err
 		}
 		return name + "(" + valuesSql + ")", nil
+	}, isDistinctAggregate: isDistinctAggregate}
+}
+
+func dialectFunction(names dialectArray, args ...interface{}) expression {
+	return expression{builder: func(scope scope) (string, error) {
+		return function(names[getDialect(scope)], args...).GetSQL(scope)
 	}}
 }
 
@@ -15,6 +27,15 @@ func Function(name string, args ...interface{}) UnknownExpression {
 	return function(name, args...)
 }
 
+// SchemaFunction creates an expression of the call to a function in the schema, e.g. "extensions"."uuid_generate_v4"().
+// Both the schema and the function name are quoted, so they are case-sensitive on PostgreSQL.
+func SchemaFunction(schema string, name string, args ...interface{}) UnknownExpression {
+	return expression{builder: func(scope scope) (string, error) {
+		dialect := getDialect(scope)
+		return function(quoteIdentifier(schema)[dialect]+"."+quoteIdentifier(name)[dialect], args...).GetSQL(scope)
+	}}
+}
+
 // Concat creates an expression of CONCAT function.
 func Concat(args ...interface{}) StringExpression {
 	return function("CONCAT", args...)
@@ -25,6 +46,22 @@ func Count(arg interface{}) NumberExpression {
 	return function("COUNT", arg)
 }
 
+// Neg creates an expression of unary minus of the value, which can be a literal or an expression.
+func Neg(value interface{}) NumberExpression {
+	return expression{builder: func(scope scope) (string, error) {
+		sql, priority, err := getSQL(scope, value)
+		if err != nil {
+			return "", err
+		}
+		return expression{sql: sql, priority: priority}.Negate().GetSQL(scope)
+	}, priority: 4}
+}
+
+// CountAll creates an expression of COUNT(*) aggregator.
+func CountAll() NumberExpression {
+	return staticExpression("COUNT(*)", 0, false)
+}
+
 // If creates an expression of IF function.
 func If(predicate Expression, trueValue interface{}, falseValue interface{}) (result UnknownExpression) {
 	return function("IF", predicate, trueValue, falseValue)
@@ -39,3 +76,59 @@ func Length(arg interface{}) NumberExpression {
 func Sum(arg interface{}) NumberExpression {
 	return function("SUM", arg)
 }
+
+// Greatest creates an expression of GREATEST function, which is MAX on SQLite.
+func Greatest(values ...interface{}) NumberExpression {
+	return dialectFunction(dialectArray{
+		dialectUnknown:  "GREATEST",
+		dialectMySQL:    "GREATEST",
+		dialectSqlite3:  "MAX",
+		dialectPostgres: "GREATEST",
+		dialectMSSQL:    "GREATEST",
+	}, values...)
+}
+
+// Least creates an expression of LEAST function, which is MIN on SQLite.
+func Least(values ...interface{}) NumberExpression {
+	return dialectFunction(dialectArray{
+		dialectUnknown:  "LEAST",
+		dialectMySQL:    "LEAST",
+		dialectSqlite3:  "MIN",
+		dialectPostgres: "LEAST",
+		dialectMSSQL:    "LEAST",
+	}, values...)
+}
+
+// AggregateExpression is the interface of a call to an aggregate function.
+type AggregateExpression interface {
+	UnknownExpression
+	WithinGroupOrderBy(orderBys ...OrderBy) UnknownExpression
+}
+
+type aggregateExpression struct {
+	expression
+}
+
+// Aggregate creates an expression of the call to specified aggregate function,
+// e.g. Aggregate("mode").WithinGroupOrderBy(field) for an ordered-set aggregate.
+func Aggregate(name string, args ...interface{}) AggregateExpression {
+	return aggregateExpression{function(name, args...)}
+}
+
+// WithinGroupOrderBy appends a WITHIN GROUP (ORDER BY ...) clause, which is supported by PostgreSQL and SQL Server.
+func (a aggregateExpression) WithinGroupOrderBy(orderBys ...OrderBy) UnknownExpression {
+	return expression{builder: func(scope scope) (string, error) {
+		if err := checkDialect(scope, "WITHIN GROUP", dialectPostgres, dialectMSSQL); err != nil {
+			return "", err
+		}
+		aggregateSql, err := a.expression.GetSQL(scope)
+		if err != nil {
+			return "", err
+		}
+		orderBySql, err := commaOrderBys(scope, orderBys)
+		if err != nil {
+			return "", err
+		}
+		return aggregateSql + " WITHIN GROUP (ORDER BY " + orderBySql + ")", nil
+	}}
+}
@@ -19,7 +19,49 @@ func TestFunction(t *testing.T) {
 
 	assertValue(t, Concat(a1, a2), "CONCAT(a1, a2)")
 	assertValue(t, Count(a1), "COUNT(a1)")
+	assertValue(t, CountAll(), "COUNT(*)")
 	assertValue(t, If(a1, 1, 2), "IF(a1, 1, 2)")
 	assertValue(t, Length(a1), "LENGTH(a1)")
 	assertValue(t, Sum(a1), "SUM(a1)")
 }
+
+func TestGreatestLeast(t *testing.T) {
+	a1 := expression{sql: "a1"}
+
+	assertValue(t, Greatest(a1, 2, "3"), "GREATEST(a1, 2, '3')")
+	assertValue(t, Least(a1, a1.Add(1)), "LEAST(a1, a1 + 1)")
+	assertDialectValue(t, dialectSqlite3, Greatest(a1, 2), "MAX(a1, 2)")
+	assertDialectValue(t, dialectSqlite3, Least(a1, 2), "MIN(a1, 2)")
+	assertDialectValue(t, dialectPostgres, Greatest(a1, 2), "GREATEST(a1, 2)")
+}
+
+func TestAggregate(t *testing.T) {
+	a1 := expression{sql: "a1"}
+
+	assertValue(t, Aggregate("mode"), "mode()")
+	assertDialectValue(t, dialectPostgres, Aggregate("mode").WithinGroupOrderBy(a1), "mode() WITHIN GROUP (ORDER BY a1)")
+	assertDialectValue(t, dialectPostgres, Aggregate("rank", 3).WithinGroupOrderBy(a1.Desc()), "rank(3) WITHIN GROUP (ORDER BY a1 DESC)")
+	assertDialectValue(t, dialectMSSQL, Aggregate("STRING_AGG", a1, ",").WithinGroupOrderBy(a1), "STRING_AGG(a1, ',') WITHIN GROUP (ORDER BY a1)")
+	assertDialectError(t, dialectMySQL, Aggregate("mode").WithinGroupOrderBy(a1))
+	assertDialectError(t, dialectSqlite3, Aggregate("mode").WithinGroupOrderBy(a1))
+}
+
+func TestAnyValue(t *testing.T) {
+	a1 := expression{sql: "a1"}
+
+	assertValue(t, a1.AnyValue(), "ANY_VALUE(a1)")
+	assertDialectValue(t, dialectPostgres, a1.AnyValue(), "MIN(a1)")
+	assertDialectValue(t, dialectMSSQL, a1.AnyValue(), "MIN(a1)")
+
+	db := newMockDatabase()
+	_, _ = db.Select(field1, field2.AnyValue()).From(table1).GroupBy(field1).FetchAll()
+	assertLastSql(t, "SELECT `field1`, ANY_VALUE(`field2`) FROM `table1` GROUP BY `field1`")
+}
+
+func TestSchemaFunction(t *testing.T) {
+	a1 := expression{sql: "a1"}
+
+	assertValue(t, SchemaFunction("util", "f", a1, 1), "`util`.`f`(a1, 1)")
+	assertDialectValue(t, dialectPostgres, SchemaFunction("extensions", "uuid_generate_v4"), `"extensions"."uuid_generate_v4"()`)
+	assertDialectValue(t, dialectMSSQL, SchemaFunction("dbo", "f", "x"), "[dbo].[f]('x')")
+}
@@ -10,12 +10,14 @@ type options struct {
 	dataSourceName string
 	tableNames     []string
 	forceCases     []string
+	// qualifyDatabase makes the generated tables qualified with the name of their database.
+	qualifyDatabase bool
 }
 
 func printUsageAndExit(exampleDataSourceName string) {
 	cmd := os.Args[0]
 	_, _ = fmt.Fprintf(os.Stderr, `Usage:
-	%s [-t table1,table2,...] [-forcecases ID,IDs,HTML] dataSourceName
+	%s [-t table1,table2,...] [-forcecases ID,IDs,HTML] [-typemap sqlType=goType/importPath,...] [-qualifydatabase] [-jsontags] [-jsontagstyle snake|camel] dataSourceName
 Example:
 	%s "%s"
 `, cmd, cmd, exampleDataSourceName)
@@ -26,6 +28,8 @@ func parseArgs(exampleDataSourceName string) (options options) {
 	var args []string
 	parseTable := false
 	parseForceCases := false
+	parseTypeMap := false
+	parseJSONTagStyle := false
 	for _, arg := range os.Args[1:] {
 		if arg != "" && arg[0] == '-' {
 			switch arg[1:] {
@@ -39,8 +43,24 @@ func parseArgs(exampleDataSourceName string) (options options) {
 					printUsageAndExit(exampleDataSourceName)
 				}
 				parseForceCases = true
+			case "typemap":
+				if parseTypeMap {
+					printUsageAndExit(exampleDataSourceName)
+				}
+				parseTypeMap = true
 			case "timeAsString":
 				timeAsString = true
+			case "qualifydatabase":
+				options.qualifyDatabase = true
+			case "jsontags":
+				if jsonTagStyle == "" {
+					jsonTagStyle = "snake"
+				}
+			case "jsontagstyle":
+				if parseJSONTagStyle {
+					printUsageAndExit(exampleDataSourceName)
+				}
+				parseJSONTagStyle = true
 			default:
 				printUsageAndExit(exampleDataSourceName)
 			}
@@ -51,12 +71,28 @@ func parseArgs(exampleDataSourceName string) (options options) {
 			} else if parseForceCases {
 				options.forceCases = append(options.forceCases, strings.Split(arg, ",")...)
 				parseForceCases = false
+			} else if parseJSONTagStyle {
+				if arg != "snake" && arg != "camel" {
+					printUsageAndExit(exampleDataSourceName)
+				}
+				jsonTagStyle = arg
+				parseJSONTagStyle = false
+			} else if parseTypeMap {
+				for _, item := range strings.Split(arg, ",") {
+					sqlType, mapping, err := parseTypeMapping(item)
+					if err != nil {
+						_, _ = fmt.Fprintln(os.Stderr, err)
+						printUsageAndExit(exampleDataSourceName)
+					}
+					typeMappings[sqlType] = mapping
+				}
+				parseTypeMap = false
 			} else {
 				args = append(args, arg)
 			}
 		}
 	}
-	if parseTable || parseForceCases {
+	if parseTable || parseForceCases || parseTypeMap || parseJSONTagStyle {
 		// "-t" not closed
 		printUsageAndExit(exampleDataSourceName)
 	}
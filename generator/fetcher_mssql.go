@@ -0,0 +1,93 @@
+package generator
+
+import (
+	"database/sql"
+	"strings"
+)
+
+type mssqlSchemaFetcher struct {
+	db *sql.DB
+}
+
+func (m mssqlSchemaFetcher) GetDatabaseName() (dbName string, err error) {
+	row := m.db.QueryRow("SELECT DB_NAME()")
+	err = row.Scan(&dbName)
+	return
+}
+
+func (m mssqlSchemaFetcher) GetTableNames() (tableNames []string, err error) {
+	rows, err := m.db.Query("SELECT TABLE_NAME FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_TYPE = 'BASE TABLE' ORDER BY TABLE_NAME")
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return
+		}
+		tableNames = append(tableNames, name)
+	}
+	return
+}
+
+func (m mssqlSchemaFetcher) GetFieldDescriptors(tableName string) (result []fieldDescriptor, err error) {
+	rows, err := m.db.Query("SELECT c.COLUMN_NAME, c.IS_NULLABLE, c.DATA_TYPE, "+
+		"CASE WHEN EXISTS (SELECT 1 FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS tc JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE kcu "+
+		"ON kcu.CONSTRAINT_SCHEMA = tc.CONSTRAINT_SCHEMA AND kcu.CONSTRAINT_NAME = tc.CONSTRAINT_NAME "+
+		"WHERE tc.CONSTRAINT_TYPE = 'PRIMARY KEY' AND tc.TABLE_SCHEMA = c.TABLE_SCHEMA AND tc.TABLE_NAME = c.TABLE_NAME "+
+		"AND kcu.COLUMN_NAME = c.COLUMN_NAME) THEN 1 ELSE 0 END "+
+		"FROM INFORMATION_SCHEMA.COLUMNS c WHERE c.TABLE_NAME = @p1 ORDER BY c.ORDINAL_POSITION", tableName)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var fieldDescriptor fieldDescriptor
+		var isNullable string
+		if err = rows.Scan(&fieldDescriptor.Name, &isNullable, &fieldDescriptor.Type, &fieldDescriptor.PrimaryKey); err != nil {
+			return
+		}
+		fieldDescriptor.AllowNull = isNullable == "YES"
+		switch strings.ToLower(fieldDescriptor.Type) {
+		case "bit":
+			fieldDescriptor.Size = 1
+		case "tinyint":
+			// tinyint ranges from 0 to 255 on SQL Server
+			fieldDescriptor.Unsigned = true
+		case "datetime2", "smalldatetime":
+			fieldDescriptor.Type = "datetime"
+		}
+		result = append(result, fieldDescriptor)
+	}
+	return
+}
+
+func (m mssqlSchemaFetcher) GetForeignKeyDescriptors(tableName string) (result []foreignKeyDescriptor, err error) {
+	rows, err := m.db.Query("SELECT rc.CONSTRAINT_NAME, kcu.COLUMN_NAME, ccu.TABLE_NAME, ccu.COLUMN_NAME, rc.UPDATE_RULE, rc.DELETE_RULE "+
+		"FROM INFORMATION_SCHEMA.REFERENTIAL_CONSTRAINTS rc "+
+		"JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE kcu ON kcu.CONSTRAINT_SCHEMA = rc.CONSTRAINT_SCHEMA AND kcu.CONSTRAINT_NAME = rc.CONSTRAINT_NAME "+
+		"JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE ccu ON ccu.CONSTRAINT_SCHEMA = rc.UNIQUE_CONSTRAINT_SCHEMA AND ccu.CONSTRAINT_NAME = rc.UNIQUE_CONSTRAINT_NAME "+
+		"AND ccu.ORDINAL_POSITION = kcu.ORDINAL_POSITION "+
+		"WHERE kcu.TABLE_NAME = @p1 ORDER BY rc.CONSTRAINT_NAME, kcu.ORDINAL_POSITION", tableName)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var fk foreignKeyDescriptor
+		if err = rows.Scan(&fk.Name, &fk.Column, &fk.ReferencedTable, &fk.ReferencedColumn, &fk.UpdateRule, &fk.DeleteRule); err != nil {
+			return
+		}
+		result = append(result, fk)
+	}
+	return
+}
+
+func (m mssqlSchemaFetcher) QuoteIdentifier(identifier string) string {
+	return "[" + strings.ReplaceAll(identifier, "]", "]]") + "]"
+}
+
+func newMSSQLSchemaFetcher(db *sql.DB) schemaFetcher {
+	return mssqlSchemaFetcher{db: db}
+}
@@ -2,8 +2,10 @@ package generator
 
 import (
 	"database/sql"
+	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
 )
 
 var timeAsString = false
@@ -19,7 +21,7 @@ func (m mysqlSchemaFetcher) GetDatabaseName() (dbName string, err error) {
 }
 
 func (m mysqlSchemaFetcher) GetTableNames() (tableNames []string, err error) {
-	rows, err := m.db.Query("SHOW TABLES")
+	rows, err := m.db.Query("SELECT TABLE_NAME FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE() ORDER BY TABLE_NAME")
 	if err != nil {
 		return
 	}
@@ -37,61 +39,76 @@ func (m mysqlSchemaFetcher) GetTableNames() (tableNames []string, err error) {
 }
 
 func (m mysqlSchemaFetcher) GetFieldDescriptors(tableName string) ([]fieldDescriptor, error) {
-	rows, err := m.db.Query("SHOW FULL COLUMNS FROM `" + tableName + "`")
+	rows, err := m.db.Query("SELECT COLUMN_NAME, COLUMN_TYPE, IS_NULLABLE, COLUMN_COMMENT, COLUMN_KEY "+
+		"FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? ORDER BY ORDINAL_POSITION", tableName)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	var result []fieldDescriptor
 	for rows.Next() {
-		columns, err := rows.Columns()
-		if err != nil {
+		var name, columnType, isNullable, comment, key string
+		if err := rows.Scan(&name, &columnType, &isNullable, &comment, &key); err != nil {
 			return nil, err
 		}
-		var pointers []interface{}
-		for i := 0; i < len(columns); i++ {
-			var value *string
-			pointers = append(pointers, &value)
-		}
-		err = rows.Scan(pointers...)
+		fieldType, fieldSize, unsigned, err := parseMySQLColumnType(columnType)
 		if err != nil {
 			return nil, err
 		}
-		row := make(map[string]string)
-		for i, column := range columns {
-			pointer := *pointers[i].(**string)
-			if pointer != nil {
-				row[column] = *pointer
-			}
-		}
+		result = append(result, fieldDescriptor{
+			Name:       name,
+			Type:       fieldType,
+			Size:       fieldSize,
+			Unsigned:   unsigned,
+			AllowNull:  isNullable == "YES",
+			Comment:    comment,
+			PrimaryKey: key == "PRI",
+		})
+	}
+	return result, rows.Err()
+}
 
-		r, _ := regexp.Compile("([a-z]+)(\\(([0-9]+)\\))?( ([a-z]+))?")
-		submatches := r.FindStringSubmatch(row["Type"])
+var mysqlColumnTypePattern = regexp.MustCompile("([a-z]+)(\\(([0-9]+)\\))?( ([a-z]+))?")
 
-		fieldType := submatches[1]
-		fieldSize := 0
-		if submatches[3] != "" {
-			fieldSize, err = strconv.Atoi(submatches[3])
-			if err != nil {
-				return nil, err
-			}
+// parseMySQLColumnType parses a column type of information_schema.COLUMNS, e.g. "int(10) unsigned".
+func parseMySQLColumnType(columnType string) (fieldType string, fieldSize int, unsigned bool, err error) {
+	submatches := mysqlColumnTypePattern.FindStringSubmatch(columnType)
+	if submatches == nil {
+		err = fmt.Errorf("unknown column type %s", columnType)
+		return
+	}
+	fieldType = submatches[1]
+	if submatches[3] != "" {
+		if fieldSize, err = strconv.Atoi(submatches[3]); err != nil {
+			return
 		}
-		unsigned := submatches[5] == "unsigned"
+	}
+	unsigned = submatches[5] == "unsigned"
+	return
+}
 
-		result = append(result, fieldDescriptor{
-			Name:      row["Field"],
-			Type:      fieldType,
-			Size:      fieldSize,
-			Unsigned:  unsigned,
-			AllowNull: row["Null"] == "YES",
-			Comment:   row["Comment"],
-		})
+func (m mysqlSchemaFetcher) GetForeignKeyDescriptors(tableName string) (result []foreignKeyDescriptor, err error) {
+	rows, err := m.db.Query("SELECT k.CONSTRAINT_NAME, k.COLUMN_NAME, k.REFERENCED_TABLE_NAME, k.REFERENCED_COLUMN_NAME, r.UPDATE_RULE, r.DELETE_RULE "+
+		"FROM information_schema.KEY_COLUMN_USAGE k JOIN information_schema.REFERENTIAL_CONSTRAINTS r "+
+		"ON r.CONSTRAINT_SCHEMA = k.CONSTRAINT_SCHEMA AND r.CONSTRAINT_NAME = k.CONSTRAINT_NAME "+
+		"WHERE k.TABLE_SCHEMA = DATABASE() AND k.TABLE_NAME = ? ORDER BY k.CONSTRAINT_NAME, k.ORDINAL_POSITION", tableName)
+	if err != nil {
+		return
 	}
-	return result, nil
+	defer rows.Close()
+	for rows.Next() {
+		var fk foreignKeyDescriptor
+		if err = rows.Scan(&fk.Name, &fk.Column, &fk.ReferencedTable, &fk.ReferencedColumn, &fk.UpdateRule, &fk.DeleteRule); err != nil {
+			return
+		}
+		result = append(result, fk)
+	}
+	return
 }
 
 func (m mysqlSchemaFetcher) QuoteIdentifier(identifier string) string {
-	return "`" + identifier + "`"
+	return "`" + strings.ReplaceAll(identifier, "`", "``") + "`"
 }
 
 func newMySQLSchemaFetcher(db *sql.DB) schemaFetcher {
@@ -29,7 +29,12 @@ func (p postgresSchemaFetcher) GetTableNames() (tableNames []string, err error)
 }
 
 func (p postgresSchemaFetcher) GetFieldDescriptors(tableName string) (result []fieldDescriptor, err error) {
-	rows, err := p.db.Query("SELECT column_name, is_nullable, data_type FROM information_schema.columns WHERE table_schema = 'public' AND table_name = $1", tableName)
+	rows, err := p.db.Query("SELECT c.column_name, c.is_nullable, c.data_type, "+
+		"EXISTS (SELECT 1 FROM information_schema.table_constraints tc JOIN information_schema.key_column_usage kcu "+
+		"ON kcu.constraint_schema = tc.constraint_schema AND kcu.constraint_name = tc.constraint_name "+
+		"WHERE tc.constraint_type = 'PRIMARY KEY' AND tc.table_schema = c.table_schema AND tc.table_name = c.table_name "+
+		"AND kcu.column_name = c.column_name) "+
+		"FROM information_schema.columns c WHERE c.table_schema = 'public' AND c.table_name = $1 ORDER BY c.ordinal_position", tableName)
 	if err != nil {
 		return
 	}
@@ -37,7 +42,7 @@ func (p postgresSchemaFetcher) GetFieldDescriptors(tableName string) (result []f
 	for rows.Next() {
 		var fieldDescriptor fieldDescriptor
 		var isNullable string
-		if err = rows.Scan(&fieldDescriptor.Name, &isNullable, &fieldDescriptor.Type); err != nil {
+		if err = rows.Scan(&fieldDescriptor.Name, &isNullable, &fieldDescriptor.Type, &fieldDescriptor.PrimaryKey); err != nil {
 			return
 		}
 		fieldDescriptor.AllowNull = isNullable == "YES"
@@ -46,6 +51,27 @@ func (p postgresSchemaFetcher) GetFieldDescriptors(tableName string) (result []f
 	return
 }
 
+func (p postgresSchemaFetcher) GetForeignKeyDescriptors(tableName string) (result []foreignKeyDescriptor, err error) {
+	rows, err := p.db.Query("SELECT rc.constraint_name, kcu.column_name, ccu.table_name, ccu.column_name, rc.update_rule, rc.delete_rule "+
+		"FROM information_schema.referential_constraints rc "+
+		"JOIN information_schema.key_column_usage kcu ON kcu.constraint_schema = rc.constraint_schema AND kcu.constraint_name = rc.constraint_name "+
+		"JOIN information_schema.key_column_usage ccu ON ccu.constraint_schema = rc.unique_constraint_schema AND ccu.constraint_name = rc.unique_constraint_name "+
+		"AND ccu.ordinal_position = kcu.position_in_unique_constraint "+
+		"WHERE kcu.table_schema = 'public' AND kcu.table_name = $1 ORDER BY rc.constraint_name, kcu.ordinal_position", tableName)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var fk foreignKeyDescriptor
+		if err = rows.Scan(&fk.Name, &fk.Column, &fk.ReferencedTable, &fk.ReferencedColumn, &fk.UpdateRule, &fk.DeleteRule); err != nil {
+			return
+		}
+		result = append(result, fk)
+	}
+	return
+}
+
 func (p postgresSchemaFetcher) QuoteIdentifier(identifier string) string {
 	return "\"" + identifier + "\""
 }
@@ -28,7 +28,7 @@ func (s sqlite3SchemaFetcher) GetTableNames() (tableNames []string, err error) {
 }
 
 func (s sqlite3SchemaFetcher) GetFieldDescriptors(tableName string) (result []fieldDescriptor, err error) {
-	rows, err := s.db.Query("SELECT `name`, `type`, `notnull` FROM pragma_table_info('" + tableName + "')")
+	rows, err := s.db.Query("SELECT `name`, `type`, `notnull`, `pk` FROM pragma_table_info('" + tableName + "')")
 	if err != nil {
 		return
 	}
@@ -36,15 +36,33 @@ func (s sqlite3SchemaFetcher) GetFieldDescriptors(tableName string) (result []fi
 	for rows.Next() {
 		var fieldDescriptor fieldDescriptor
 		var notNull int
-		if err = rows.Scan(&fieldDescriptor.Name, &fieldDescriptor.Type, &notNull); err != nil {
+		var pk int
+		if err = rows.Scan(&fieldDescriptor.Name, &fieldDescriptor.Type, &notNull, &pk); err != nil {
 			return
 		}
 		fieldDescriptor.AllowNull = notNull == 0
+		fieldDescriptor.PrimaryKey = pk > 0
 		result = append(result, fieldDescriptor)
 	}
 	return
 }
 
+func (s sqlite3SchemaFetcher) GetForeignKeyDescriptors(tableName string) (result []foreignKeyDescriptor, err error) {
+	rows, err := s.db.Query("SELECT 'fk_' || `id`, `from`, `table`, `to`, `on_update`, `on_delete` FROM pragma_foreign_key_list('" + tableName + "') ORDER BY `id`, `seq`")
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var fk foreignKeyDescriptor
+		if err = rows.Scan(&fk.Name, &fk.Column, &fk.ReferencedTable, &fk.ReferencedColumn, &fk.UpdateRule, &fk.DeleteRule); err != nil {
+			return
+		}
+		result = append(result, fk)
+	}
+	return
+}
+
 func (s sqlite3SchemaFetcher) QuoteIdentifier(identifier string) string {
 	return "\"" + identifier + "\""
 }
@@ -7,6 +7,7 @@ import (
 	"go/format"
 	"os"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -22,16 +23,28 @@ type schemaFetcher interface {
 	GetDatabaseName() (dbName string, err error)
 	GetTableNames() (tableNames []string, err error)
 	GetFieldDescriptors(tableName string) ([]fieldDescriptor, error)
+	GetForeignKeyDescriptors(tableName string) ([]foreignKeyDescriptor, error)
 	QuoteIdentifier(identifier string) string
 }
 
 type fieldDescriptor struct {
-	Name      string
-	Type      string
-	Size      int
-	Unsigned  bool
-	AllowNull bool
-	Comment   string
+	Name       string
+	Type       string
+	Size       int
+	Unsigned   bool
+	AllowNull  bool
+	Comment    string
+	PrimaryKey bool
+}
+
+// foreignKeyDescriptor describes one column of a foreign key, with the referential actions of the constraint.
+type foreignKeyDescriptor struct {
+	Name             string
+	Column           string
+	ReferencedTable  string
+	ReferencedColumn string
+	UpdateRule       string
+	DeleteRule       string
 }
 
 func convertToExportedIdentifier(s string, forceCases []string) string {
@@ -71,7 +84,29 @@ func convertToExportedIdentifier(s string, forceCases []string) string {
 	return result
 }
 
+// typeMapping is a Go type, with the package it is imported from, that a SQL type is mapped to.
+type typeMapping struct {
+	goType     string
+	importPath string
+}
+
+// typeMappings overrides the Go types of SQL types, as given by the -typemap option.
+var typeMappings = map[string]typeMapping{}
+
+// parseTypeMapping parses a type mapping in the form of sqlType=goType or sqlType=goType/importPath,
+// e.g. numeric=decimal.Decimal/github.com/shopspring/decimal.
+func parseTypeMapping(s string) (sqlType string, mapping typeMapping, err error) {
+	sqlType, goType, ok := strings.Cut(s, "=")
+	if !ok || sqlType == "" || goType == "" {
+		err = fmt.Errorf("invalid type mapping %s", s)
+		return
+	}
+	mapping.goType, mapping.importPath, _ = strings.Cut(goType, "/")
+	return strings.ToLower(sqlType), mapping, nil
+}
+
 func getType(fieldDescriptor fieldDescriptor) (goType string, fieldClass string, fieldComment string, err error) {
+	mapping, isMapped := typeMappings[strings.ToLower(fieldDescriptor.Type)]
 	switch strings.ToLower(fieldDescriptor.Type) {
 	case "tinyint":
 		goType = "int8"
@@ -85,17 +120,18 @@ func getType(fieldDescriptor fieldDescriptor) (goType string, fieldClass string,
 	case "bigint", "integer":
 		goType = "int64"
 		fieldClass = "NumberField"
-	case "float", "double", "decimal", "real":
+	case "float", "double", "decimal", "real", "money", "smallmoney":
 		goType = "float64"
 		fieldClass = "NumberField"
-	case "char", "varchar", "text", "tinytext", "mediumtext", "longtext", "enum", "date", "time", "json", "numeric", "character varying", "timestamp without time zone", "timestamp with time zone", "jsonb", "uuid":
+	case "char", "varchar", "text", "tinytext", "mediumtext", "longtext", "enum", "date", "time", "json", "numeric", "character varying", "timestamp without time zone", "timestamp with time zone", "jsonb", "uuid",
+		"nchar", "nvarchar", "ntext", "uniqueidentifier", "datetimeoffset":
 		goType = "string"
 		fieldClass = "StringField"
 	case "year":
 		goType = "int16"
 		fieldClass = "NumberField"
 		fieldDescriptor.Unsigned = true
-	case "binary", "varbinary", "blob", "tinyblob", "mediumblob", "longblob":
+	case "binary", "varbinary", "blob", "tinyblob", "mediumblob", "longblob", "image":
 		// TODO: use []byte ?
 		goType = "string"
 		fieldClass = "StringField"
@@ -133,8 +169,16 @@ func getType(fieldDescriptor fieldDescriptor) (goType string, fieldClass string,
 			fieldClass = "StringField"
 		}
 	default:
-		err = fmt.Errorf("unknown field type %s", fieldDescriptor.Type)
-		return
+		if !isMapped {
+			err = fmt.Errorf("unknown field type %s", fieldDescriptor.Type)
+			return
+		}
+		fieldClass = "StringField"
+	}
+	if isMapped {
+		// the field class of the default mapping is kept
+		goType = mapping.goType
+		fieldComment = ""
 	}
 	if fieldDescriptor.Unsigned && strings.HasPrefix(goType, "int") {
 		goType = "u" + goType
@@ -145,14 +189,51 @@ func getType(fieldDescriptor fieldDescriptor) (goType string, fieldClass string,
 	return
 }
 
+// jsonTagStyle is the style of the json tags of the generated model fields, which are not emitted if empty.
+var jsonTagStyle = ""
+
+// getJSONTag returns the struct tag of a model field for the column, e.g. `json:"user_id"`,
+// or `json:"userId"` in the camel style.
+func getJSONTag(columnName string) string {
+	switch jsonTagStyle {
+	case "":
+		return ""
+	case "camel":
+		return " `json:" + strconv.Quote(convertToCamelCase(columnName)) + "`"
+	default:
+		return " `json:" + strconv.Quote(columnName) + "`"
+	}
+}
+
+func convertToCamelCase(s string) string {
+	var sb strings.Builder
+	nextCharShouldBeUpperCase := false
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			nextCharShouldBeUpperCase = sb.Len() > 0
+			continue
+		}
+		if sb.Len() == 0 {
+			r = unicode.ToLower(r)
+		} else if nextCharShouldBeUpperCase {
+			r = unicode.ToUpper(r)
+		}
+		nextCharShouldBeUpperCase = false
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
 func getSchemaFetcherFactory(driverName string) func(db *sql.DB) schemaFetcher {
 	switch driverName {
 	case "mysql":
 		return newMySQLSchemaFetcher
-	case "sqlite3":
+	case "sqlite3", "sqlite":
 		return newSQLite3SchemaFetcher
 	case "postgres":
 		return newPostgresSchemaFetcher
+	case "sqlserver", "mssql":
+		return newMSSQLSchemaFetcher
 	default:
 		_, _ = fmt.Fprintln(os.Stderr, "unsupported driver "+driverName)
 		os.Exit(2)
@@ -199,16 +280,21 @@ func Generate(driverName string, exampleDataSourceName string) (string, error) {
 		}
 	}
 
-	needImportTime := false
+	importPaths := map[string]bool{"github.com/lqs/sqlingo": true}
 	for _, tableName := range options.tableNames {
 		fieldDescriptors, err := schemaFetcher.GetFieldDescriptors(tableName)
 		if err != nil {
 			return "", err
 		}
 		for _, fieldDescriptor := range fieldDescriptors {
+			if mapping, ok := typeMappings[strings.ToLower(fieldDescriptor.Type)]; ok {
+				if mapping.importPath != "" {
+					importPaths[mapping.importPath] = true
+				}
+				continue
+			}
 			if !timeAsString && fieldDescriptor.Type == "datetime" || fieldDescriptor.Type == "timestamp" {
-				needImportTime = true
-				break
+				importPaths["time"] = true
 			}
 		}
 	}
@@ -216,14 +302,7 @@ func Generate(driverName string, exampleDataSourceName string) (string, error) {
 	code := "// This file is generated by sqlingo (https://github.com/lqs/sqlingo)\n"
 	code += "// DO NOT EDIT.\n\n"
 	code += "package " + ensureIdentifier(dbName) + "_dsl\n"
-	if needImportTime {
-		code += "import (\n"
-		code += "\t\"time\"\n"
-		code += "\t\"github.com/lqs/sqlingo\"\n"
-		code += ")\n\n"
-	} else {
-		code += "import \"github.com/lqs/sqlingo\"\n\n"
-	}
+	code += generateImports(importPaths)
 
 	code += "type sqlingoRuntimeAndGeneratorVersionsShouldBeTheSame uint32\n\n"
 
@@ -264,13 +343,17 @@ func Generate(driverName string, exampleDataSourceName string) (string, error) {
 	tableCodeMap := make(map[string]*tableCodeItem)
 	fmt.Fprintln(os.Stderr, "Generating code for tables...")
 	var counter int32
+	tableDatabaseName := ""
+	if options.qualifyDatabase {
+		tableDatabaseName = dbName
+	}
 	for _, tableName := range options.tableNames {
 		wg.Add(1)
 		item := &tableCodeItem{}
 		tableCodeMap[tableName] = item
 		go func(tableName string) {
 			defer wg.Done()
-			tableCode, err := generateTable(schemaFetcher, tableName, options.forceCases)
+			tableCode, err := generateTable(schemaFetcher, tableDatabaseName, tableName, options.forceCases)
 			if err != nil {
 				item.err = err
 				return
@@ -295,6 +378,20 @@ func Generate(driverName string, exampleDataSourceName string) (string, error) {
 	return string(codeOut), nil
 }
 
+func generateImports(importPaths map[string]bool) string {
+	paths := make([]string, 0, len(importPaths))
+	for path := range importPaths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	code := "import (\n"
+	for _, path := range paths {
+		code += "\t" + strconv.Quote(path) + "\n"
+	}
+	code += ")\n\n"
+	return code
+}
+
 func generateGetTable(options options) string {
 	code := "func GetTable(name string) sqlingo.Table {\n"
 	code += "\tswitch name {\n"
@@ -316,12 +413,18 @@ func generateGetTable(options options) string {
 	return code
 }
 
-func generateTable(schemaFetcher schemaFetcher, tableName string, forceCases []string) (string, error) {
+// generateTable generates the code of the table. If dbName is not empty, the table is qualified with it.
+func generateTable(schemaFetcher schemaFetcher, dbName string, tableName string, forceCases []string) (string, error) {
 	fieldDescriptors, err := schemaFetcher.GetFieldDescriptors(tableName)
 	if err != nil {
 		return "", err
 	}
 
+	foreignKeyDescriptors, err := schemaFetcher.GetForeignKeyDescriptors(tableName)
+	if err != nil {
+		return "", err
+	}
+
 	className := convertToExportedIdentifier(tableName, forceCases)
 	tableStructName := "t" + className
 	tableObjectName := "o" + className
@@ -333,12 +436,23 @@ func generateTable(schemaFetcher schemaFetcher, tableName string, forceCases []s
 	objectLines := "\ttable: " + tableObjectName + ",\n\n"
 	fieldCaseLines := ""
 	classLines := ""
+	columnsLines := ""
+	columnsValues := ""
+	hasColumnsField := false
 
 	fields := ""
+	primaryKeyFields := ""
 	fieldsSQL := ""
 	fullFieldsSQL := ""
 	values := ""
 
+	tableSQL := schemaFetcher.QuoteIdentifier(tableName)
+	newTableCode := "sqlingo.NewTable(" + strconv.Quote(tableName) + ")"
+	if dbName != "" {
+		tableSQL = schemaFetcher.QuoteIdentifier(dbName) + "." + tableSQL
+		newTableCode = "sqlingo.NewTableInDatabase(" + strconv.Quote(dbName) + ", " + strconv.Quote(tableName) + ")"
+	}
+
 	for _, fieldDescriptor := range fieldDescriptors {
 
 		goName := convertToExportedIdentifier(fieldDescriptor.Name, forceCases)
@@ -363,7 +477,7 @@ func generateTable(schemaFetcher schemaFetcher, tableName string, forceCases []s
 		tableLines += "\t" + goName + " " + fieldStructName + "\n"
 
 		modelLines += commentLine
-		modelLines += "\t" + goName + " " + goType + "\n"
+		modelLines += "\t" + goName + " " + goType + getJSONTag(fieldDescriptor.Name) + "\n"
 
 		objectLines += commentLine
 		objectLines += "\t" + goName + ": " + fieldStructName + "{"
@@ -373,7 +487,17 @@ func generateTable(schemaFetcher schemaFetcher, tableName string, forceCases []s
 
 		classLines += "type " + fieldStructName + " struct{ " + privateFieldClass + " }\n"
 
+		columnsLines += commentLine
+		columnsLines += "\t" + goName + " " + fieldStructName + "\n"
+		columnsValues += goName + ": " + className + "." + goName + ", "
+		if goName == "Columns" {
+			hasColumnsField = true
+		}
+
 		fields += "t." + goName + ", "
+		if fieldDescriptor.PrimaryKey {
+			primaryKeyFields += "t." + goName + ", "
+		}
 
 		if fieldsSQL != "" {
 			fieldsSQL += ", "
@@ -383,26 +507,52 @@ func generateTable(schemaFetcher schemaFetcher, tableName string, forceCases []s
 		if fullFieldsSQL != "" {
 			fullFieldsSQL += ", "
 		}
-		fullFieldsSQL += schemaFetcher.QuoteIdentifier(tableName) + "." + schemaFetcher.QuoteIdentifier(fieldDescriptor.Name)
+		fullFieldsSQL += tableSQL + "." + schemaFetcher.QuoteIdentifier(fieldDescriptor.Name)
 
 		values += "m." + goName + ", "
 	}
 	code := ""
+	code += generateForeignKeyComments(foreignKeyDescriptors)
 	code += "type " + tableStructName + " struct {\n\ttable\n\n"
 	code += tableLines
+	if !hasColumnsField {
+		// a column named columns would clash with the accessor
+		code += "\n\t// Columns enumerates the fields of the table for partial selects, e.g. Select(t.Columns.Id).\n"
+		code += "\tColumns " + tableStructName + "Columns\n"
+	}
 	code += "}\n\n"
 
+	if !hasColumnsField {
+		code += "type " + tableStructName + "Columns struct {\n"
+		code += columnsLines
+		code += "}\n\n"
+	}
+
 	code += classLines
 
-	code += "var " + tableObjectName + " = sqlingo.NewTable(" + strconv.Quote(tableName) + ")\n"
+	code += "var " + tableObjectName + " = " + newTableCode + "\n"
 	code += "var " + className + " = " + tableStructName + "{\n"
 	code += objectLines
 	code += "}\n\n"
 
+	if !hasColumnsField {
+		code += "func init() {\n"
+		code += "\t" + className + ".Columns = " + tableStructName + "Columns{" + columnsValues + "}\n"
+		code += "}\n\n"
+	}
+
 	code += "func (t t" + className + ") GetFields() []sqlingo.Field {\n"
 	code += "\treturn []sqlingo.Field{" + fields + "}\n"
 	code += "}\n\n"
 
+	code += "func (t t" + className + ") AllFieldsExcept(excluded ...sqlingo.Field) []sqlingo.Field {\n"
+	code += "\treturn sqlingo.AllFieldsExcept(t, excluded...)\n"
+	code += "}\n\n"
+
+	code += "func (t t" + className + ") GetPrimaryKeyFields() []sqlingo.Field {\n"
+	code += "\treturn []sqlingo.Field{" + primaryKeyFields + "}\n"
+	code += "}\n\n"
+
 	code += "func (t t" + className + ") GetFieldByName(name string) sqlingo.Field {\n"
 	code += "\tswitch name {\n"
 	code += fieldCaseLines
@@ -432,6 +582,28 @@ func generateTable(schemaFetcher schemaFetcher, tableName string, forceCases []s
 	return code, nil
 }
 
+// generateForeignKeyComments documents the foreign keys of a table, including the
+// ON UPDATE and ON DELETE actions, which are otherwise invisible in the generated code.
+func generateForeignKeyComments(foreignKeyDescriptors []foreignKeyDescriptor) string {
+	if len(foreignKeyDescriptors) == 0 {
+		return ""
+	}
+	code := "// Foreign keys:\n"
+	for i := 0; i < len(foreignKeyDescriptors); {
+		name := foreignKeyDescriptors[i].Name
+		var columns, referencedColumns []string
+		first := foreignKeyDescriptors[i]
+		for ; i < len(foreignKeyDescriptors) && foreignKeyDescriptors[i].Name == name; i++ {
+			columns = append(columns, foreignKeyDescriptors[i].Column)
+			referencedColumns = append(referencedColumns, foreignKeyDescriptors[i].ReferencedColumn)
+		}
+		code += "//   " + name + ": (" + strings.Join(columns, ", ") + ") REFERENCES " +
+			first.ReferencedTable + " (" + strings.Join(referencedColumns, ", ") + ")" +
+			" ON UPDATE " + first.UpdateRule + " ON DELETE " + first.DeleteRule + "\n"
+	}
+	return code
+}
+
 // replaceTypeSpace : To compatible some types contains spaces in postgresql
 // like [character varying, timestamp without time zone, timestamp with time zone]
 func replaceTypeSpace(typename string) string {
@@ -1,6 +1,11 @@
 package generator
 
-import "testing"
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
 
 func TestConvert(t *testing.T) {
 	m := map[string]string{
@@ -26,3 +31,143 @@ func TestConvert(t *testing.T) {
 		}
 	}
 }
+
+func TestGenerateForeignKeyComments(t *testing.T) {
+	if code := generateForeignKeyComments(nil); code != "" {
+		t.Errorf("unexpected code %q", code)
+	}
+	code := generateForeignKeyComments([]foreignKeyDescriptor{
+		{Name: "fk_a", Column: "a1", ReferencedTable: "t1", ReferencedColumn: "id1", UpdateRule: "CASCADE", DeleteRule: "RESTRICT"},
+		{Name: "fk_a", Column: "a2", ReferencedTable: "t1", ReferencedColumn: "id2", UpdateRule: "CASCADE", DeleteRule: "RESTRICT"},
+		{Name: "fk_b", Column: "b", ReferencedTable: "t2", ReferencedColumn: "id", UpdateRule: "NO ACTION", DeleteRule: "SET NULL"},
+	})
+	expected := "// Foreign keys:\n" +
+		"//   fk_a: (a1, a2) REFERENCES t1 (id1, id2) ON UPDATE CASCADE ON DELETE RESTRICT\n" +
+		"//   fk_b: (b) REFERENCES t2 (id) ON UPDATE NO ACTION ON DELETE SET NULL\n"
+	if code != expected {
+		t.Errorf("generated %q expected %q", code, expected)
+	}
+}
+
+func TestTypeMapping(t *testing.T) {
+	sqlType, mapping, err := parseTypeMapping("DECIMAL=decimal.Decimal/github.com/shopspring/decimal")
+	if err != nil || sqlType != "decimal" || mapping.goType != "decimal.Decimal" || mapping.importPath != "github.com/shopspring/decimal" {
+		t.Errorf("unexpected mapping %s %+v %v", sqlType, mapping, err)
+	}
+	if _, _, err := parseTypeMapping("jsonb"); err == nil {
+		t.Error("should fail without Go type")
+	}
+
+	typeMappings = map[string]typeMapping{
+		"decimal": mapping,
+		"citext":  {goType: "string"},
+	}
+	defer func() {
+		typeMappings = map[string]typeMapping{}
+	}()
+
+	if goType, fieldClass, _, err := getType(fieldDescriptor{Type: "decimal", AllowNull: true}); err != nil || goType != "*decimal.Decimal" || fieldClass != "NumberField" {
+		t.Errorf("unexpected type %s %s %v", goType, fieldClass, err)
+	}
+	if goType, fieldClass, _, err := getType(fieldDescriptor{Type: "citext"}); err != nil || goType != "string" || fieldClass != "StringField" {
+		t.Errorf("unexpected type %s %s %v", goType, fieldClass, err)
+	}
+	if _, _, _, err := getType(fieldDescriptor{Type: "tsvector"}); err == nil {
+		t.Error("should fail for unknown type")
+	}
+	if code := generateImports(map[string]bool{"time": true, "github.com/lqs/sqlingo": true}); code != "import (\n\t\"github.com/lqs/sqlingo\"\n\t\"time\"\n)\n\n" {
+		t.Errorf("unexpected imports %q", code)
+	}
+}
+
+func TestJSONTag(t *testing.T) {
+	if tag := getJSONTag("user_id"); tag != "" {
+		t.Errorf("unexpected tag %q", tag)
+	}
+	defer func() {
+		jsonTagStyle = ""
+	}()
+	jsonTagStyle = "snake"
+	if tag := getJSONTag("user_id"); tag != " `json:\"user_id\"`" {
+		t.Errorf("unexpected tag %q", tag)
+	}
+	jsonTagStyle = "camel"
+	m := map[string]string{
+		"user_id":      "userId",
+		"UserName":     "userName",
+		"_created__at": "createdAt",
+		"id":           "id",
+	}
+	for k, v := range m {
+		if tag := getJSONTag(k); tag != " `json:\""+v+"\"`" {
+			t.Errorf("'%s' should be tagged as '%s', got %q", k, v, tag)
+		}
+	}
+}
+
+func TestParseMySQLColumnType(t *testing.T) {
+	for _, test := range []struct {
+		columnType string
+		fieldType  string
+		size       int
+		unsigned   bool
+	}{
+		{"int(10) unsigned", "int", 10, true},
+		{"bigint", "bigint", 0, false},
+		{"varchar(255)", "varchar", 255, false},
+		{"tinyint(1)", "tinyint", 1, false},
+	} {
+		fieldType, size, unsigned, err := parseMySQLColumnType(test.columnType)
+		if err != nil || fieldType != test.fieldType || size != test.size || unsigned != test.unsigned {
+			t.Error(test.columnType, fieldType, size, unsigned, err)
+		}
+	}
+	if _, _, _, err := parseMySQLColumnType("?"); err == nil {
+		t.Error("should fail on an unknown column type")
+	}
+}
+
+type fakeSchemaFetcher struct {
+	fields []fieldDescriptor
+}
+
+func (f fakeSchemaFetcher) GetDatabaseName() (string, error) { return "db", nil }
+
+func (f fakeSchemaFetcher) GetTableNames() ([]string, error) { return []string{"user"}, nil }
+
+func (f fakeSchemaFetcher) GetFieldDescriptors(tableName string) ([]fieldDescriptor, error) {
+	return f.fields, nil
+}
+
+func (f fakeSchemaFetcher) GetForeignKeyDescriptors(tableName string) ([]foreignKeyDescriptor, error) {
+	return nil, nil
+}
+
+func (f fakeSchemaFetcher) QuoteIdentifier(identifier string) string { return "`" + identifier + "`" }
+
+func TestGenerateTableColumns(t *testing.T) {
+	code, err := generateTable(fakeSchemaFetcher{fields: []fieldDescriptor{
+		{Name: "id", Type: "bigint", PrimaryKey: true},
+		{Name: "name", Type: "varchar"},
+	}}, "", "user", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"\tColumns tUserColumns\n",
+		"type tUserColumns struct {\n\tId bigint_User_Id\n\tName varchar_User_Name\n}\n",
+		"func init() {\n\tUser.Columns = tUserColumns{Id: User.Id, Name: User.Name, }\n}\n",
+	} {
+		if !strings.Contains(code, expected) {
+			t.Errorf("%q is not generated in %s", expected, code)
+		}
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "user.go", "package db_dsl\n"+code, 0); err != nil {
+		t.Error(err)
+	}
+
+	code, err = generateTable(fakeSchemaFetcher{fields: []fieldDescriptor{{Name: "columns", Type: "int"}}}, "", "user", nil)
+	if err != nil || strings.Contains(code, "tUserColumns") {
+		t.Error("should not generate the accessor clashing with a column", err)
+	}
+}
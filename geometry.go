@@ -19,7 +19,7 @@ type WellKnownBinary []byte
 
 // NewWellKnownBinaryField creates a reference to a geometry WKB field. It should only be called from generated code.
 func NewWellKnownBinaryField(table Table, fieldName string) WellKnownBinaryField {
-	return newField(table, fieldName)
+	return newField(table, fieldName, columnBinary)
 }
 
 func (e expression) STAsText() StringExpression {
@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 )
 
 type insertStatus struct {
@@ -14,7 +16,15 @@ type insertStatus struct {
 	fields                          []Field
 	values                          []interface{}
 	models                          []interface{}
+	rows                            []map[string]interface{}
 	onDuplicateKeyUpdateAssignments []assignment
+	onConflict                      *onConflict
+	ignoreDuplicates                bool
+	returning                       []Field
+	batchSize                       int
+	batch                           *int
+	prepared                        *insertValues
+	routeHint                       string
 	ctx                             context.Context
 }
 
@@ -22,6 +32,7 @@ type insertWithTable interface {
 	Fields(fields ...Field) insertWithValues
 	Values(values ...interface{}) insertWithValues
 	Models(models ...interface{}) insertWithModels
+	Rows(rows []map[string]interface{}) insertWithRows
 }
 
 type insertWithValues interface {
@@ -30,6 +41,10 @@ type insertWithValues interface {
 	Values(values ...interface{}) insertWithValues
 	OnDuplicateKeyIgnore() toInsertWithDuplicateKey
 	OnDuplicateKeyUpdate() insertWithOnDuplicateKeyUpdateBegin
+	OnConflict(target ...Field) insertWithOnConflict
+	IgnoreDuplicates() toInsertWithDuplicateKey
+	Returning(fields ...Field) toReturningWithContext
+	BatchSize(size int) toInsertWithDuplicateKey
 }
 
 type insertWithModels interface {
@@ -38,6 +53,32 @@ type insertWithModels interface {
 	Models(models ...interface{}) insertWithModels
 	OnDuplicateKeyIgnore() toInsertWithDuplicateKey
 	OnDuplicateKeyUpdate() insertWithOnDuplicateKeyUpdateBegin
+	OnConflict(target ...Field) insertWithOnConflict
+	IgnoreDuplicates() toInsertWithDuplicateKey
+	Returning(fields ...Field) toReturningWithContext
+	BatchSize(size int) toInsertWithDuplicateKey
+}
+
+type insertWithRows interface {
+	toInsertWithContext
+	toInsertFinal
+	OnDuplicateKeyIgnore() toInsertWithDuplicateKey
+	OnDuplicateKeyUpdate() insertWithOnDuplicateKeyUpdateBegin
+	OnConflict(target ...Field) insertWithOnConflict
+	IgnoreDuplicates() toInsertWithDuplicateKey
+	Returning(fields ...Field) toReturningWithContext
+	BatchSize(size int) toInsertWithDuplicateKey
+}
+
+type insertWithOnConflict interface {
+	DoNothing() toInsertWithDuplicateKey
+	DoUpdateSet(field Field, value interface{}) insertWithOnConflictDoUpdate
+}
+
+type insertWithOnConflictDoUpdate interface {
+	toInsertWithDuplicateKey
+	DoUpdateSet(field Field, value interface{}) insertWithOnConflictDoUpdate
+	Where(conditions ...BooleanExpression) toInsertWithDuplicateKey
 }
 
 type insertWithOnDuplicateKeyUpdateBegin interface {
@@ -55,13 +96,19 @@ type toInsertWithContext interface {
 }
 
 type toInsertFinal interface {
+	RouteHint(hint string) toInsertFinal
 	GetSQL() (string, error)
+	GetSQLWithArgs() (string, []interface{}, error)
 	Execute() (result sql.Result, err error)
+	// Stats is only offered by inserts, as they are the statements whose size grows with the rows
+	// and which are split by BatchSize. The size of the other statements does not depend on the data.
+	Stats() (sqlLen int, paramCount int, rowCount int, err error)
 }
 
 type toInsertWithDuplicateKey interface {
 	toInsertWithContext
 	toInsertFinal
+	BatchSize(size int) toInsertWithDuplicateKey
 }
 
 func (d *database) InsertInto(table Table) insertWithTable {
@@ -114,6 +161,75 @@ func (s insertStatus) Models(models ...interface{}) insertWithModels {
 	return s
 }
 
+// Rows inserts rows given as maps from column names to values. The columns are the union of the keys
+// in sorted order, and the columns missing in a row are filled with DEFAULT (NULL on SQLite).
+func (s insertStatus) Rows(rows []map[string]interface{}) insertWithRows {
+	s.rows = rows
+	return s
+}
+
+func (s insertStatus) getRowsFieldsAndValues() (fieldsSql string, values []interface{}, err error) {
+	table := s.scope.Tables[0]
+	var knownColumns map[string]bool
+	if tableFields := table.GetFields(); len(tableFields) > 0 {
+		knownColumns = make(map[string]bool, len(tableFields))
+		for _, field := range tableFields {
+			name, err := getFieldName(field)
+			if err != nil {
+				return "", nil, err
+			}
+			knownColumns[name] = true
+		}
+	}
+
+	var columns []string
+	columnSet := make(map[string]bool)
+	for _, row := range s.rows {
+		for column := range row {
+			if columnSet[column] {
+				continue
+			}
+			if knownColumns != nil && !knownColumns[column] {
+				return "", nil, fmt.Errorf("unknown column %s in table %s", column, table.GetName())
+			}
+			columnSet[column] = true
+			columns = append(columns, column)
+		}
+	}
+	sort.Strings(columns)
+
+	dialect := getDialect(s.scope)
+	missingValue := staticExpression("DEFAULT", 0, false)
+	if dialect == dialectSqlite3 {
+		missingValue = staticExpression("NULL", 0, false)
+	}
+
+	quotedColumns := make([]string, len(columns))
+	for i, column := range columns {
+		quotedColumns[i] = quoteIdentifier(column)[dialect]
+	}
+	values = make([]interface{}, len(s.rows))
+	for i, row := range s.rows {
+		rowValues := make([]interface{}, len(columns))
+		for j, column := range columns {
+			if value, ok := row[column]; ok {
+				rowValues[j] = value
+			} else {
+				rowValues[j] = missingValue
+			}
+		}
+		values[i] = rowValues
+	}
+	return strings.Join(quotedColumns, ", "), values, nil
+}
+
+// Returning appends a RETURNING clause, and the statement is executed as a query to fetch the returned rows.
+// It is supported by PostgreSQL and SQLite.
+func (s insertStatus) Returning(fields ...Field) toReturningWithContext {
+	s.returning = fields
+	return returningStatus{database: s.scope.Database, statement: s}
+}
+
 func (s insertStatus) OnDuplicateKeyUpdate() insertWithOnDuplicateKeyUpdateBegin {
 	return s
 }
@@ -134,19 +250,115 @@ func (s insertStatus) Set(field Field, value interface{}) insertWithOnDuplicateK
 	return s
 }
 
+type onConflict struct {
+	target    []Field
+	doNothing bool
+	where     BooleanExpression
+}
+
+// OnConflict handles the conflicts on the target columns, e.g. ON CONFLICT (id) DO UPDATE SET ... on PostgreSQL
+// and SQLite. On MySQL, it is rendered as ON DUPLICATE KEY UPDATE, where the target is determined by the unique keys.
+// The target may be omitted for DoNothing, but DoUpdateSet requires it on PostgreSQL and SQLite.
+func (s insertStatus) OnConflict(target ...Field) insertWithOnConflict {
+	s.onConflict = &onConflict{target: target}
+	return s
+}
+
+func (s insertStatus) DoNothing() toInsertWithDuplicateKey {
+	s.onConflict = &onConflict{target: s.onConflict.target, doNothing: true}
+	return s
+}
+
+// Where makes the conflicting rows updated only if the conditions hold, e.g. to keep the rows newer than
+// the inserted ones. It is supported by PostgreSQL and SQLite.
+func (s insertStatus) Where(conditions ...BooleanExpression) toInsertWithDuplicateKey {
+	s.onConflict = &onConflict{target: s.onConflict.target, where: And(conditions...)}
+	return s
+}
+
+// Excluded references the value proposed for insertion into the field, for the assignments of DoUpdateSet
+// and OnDuplicateKeyUpdate. It is rendered as VALUES(field) on MySQL and excluded.field on PostgreSQL and SQLite.
+func Excluded(field Field) UnknownExpression {
+	return expression{builder: func(scope scope) (string, error) {
+		name, err := getFieldName(field)
+		if err != nil {
+			return "", err
+		}
+		dialect := getDialect(scope)
+		if dialect == dialectMySQL || dialect == dialectUnknown {
+			return "VALUES(" + quoteIdentifier(name)[dialect] + ")", nil
+		}
+		return "excluded." + quoteIdentifier(name)[dialect], nil
+	}}
+}
+
+func (s insertStatus) DoUpdateSet(field Field, value interface{}) insertWithOnConflictDoUpdate {
+	s.onDuplicateKeyUpdateAssignments = append([]assignment{}, s.onDuplicateKeyUpdateAssignments...)
+	s.onDuplicateKeyUpdateAssignments = append(s.onDuplicateKeyUpdateAssignments, assignment{
+		field: field,
+		value: value,
+	})
+	return s
+}
+
+// IgnoreDuplicates skips the rows conflicting with existing ones, as INSERT IGNORE on MySQL,
+// INSERT OR IGNORE on SQLite and ON CONFLICT DO NOTHING on PostgreSQL.
+func (s insertStatus) IgnoreDuplicates() toInsertWithDuplicateKey {
+	s.ignoreDuplicates = true
+	return s
+}
+
 func (s insertStatus) OnDuplicateKeyIgnore() toInsertWithDuplicateKey {
 	firstField := s.scope.Tables[0].GetFields()[0]
 	return s.OnDuplicateKeyUpdate().Set(firstField, firstField)
 }
 
+// RouteHint prepends the hint as a comment to the statement for a sharding proxy, e.g. /* shard=users_3 */.
+func (s insertStatus) RouteHint(hint string) toInsertFinal {
+	s.routeHint = hint
+	return s
+}
+
 func (s insertStatus) GetSQL() (string, error) {
+	return s.scope.Database.finishSQL(s.buildSQL(nil))
+}
+
+// GetSQLWithArgs returns the SQL with placeholders and the values bound to them.
+func (s insertStatus) GetSQLWithArgs() (string, []interface{}, error) {
+	args := &argList{}
+	sqlString, err := s.scope.Database.finishSQL(s.buildSQL(args))
+	return sqlString, args.values, err
+}
+
+// insertValues keeps the fields and the rows of values of an insert that are built once for its batches.
+type insertValues struct {
+	fieldsSql string
+	values    []interface{}
+}
+
+// prepare builds the fields and the rows of values, which are reused by the later builds of the statement.
+func (s insertStatus) prepare() (insertStatus, error) {
+	fieldsSql, values, err := s.getFieldsAndValues()
+	if err != nil {
+		return s, err
+	}
+	s.prepared = &insertValues{fieldsSql: fieldsSql, values: values}
+	return s, nil
+}
+
+func (s insertStatus) getFieldsAndValues() (fieldsSql string, values []interface{}, err error) {
+	if s.prepared != nil {
+		return s.prepared.fieldsSql, s.prepared.values, nil
+	}
+	if len(s.rows) > 0 {
+		return s.getRowsFieldsAndValues()
+	}
 	var fields []Field
-	var values []interface{}
 	if len(s.models) > 0 {
 		models := make([]Model, 0, len(s.models))
 		for _, model := range s.models {
 			if err := addModel(&models, model); err != nil {
-				return "", err
+				return "", nil, err
 			}
 		}
 
@@ -154,7 +366,7 @@ func (s insertStatus) GetSQL() (string, error) {
 			fields = models[0].GetTable().GetFields()
 			for _, model := range models {
 				if model.GetTable().GetName() != s.scope.Tables[0].GetName() {
-					return "", errors.New("invalid table from model")
+					return "", nil, errors.New("invalid table from model")
 				}
 				values = append(values, model.GetValues())
 			}
@@ -167,31 +379,138 @@ func (s insertStatus) GetSQL() (string, error) {
 		}
 		values = s.values
 	}
-
 	if len(values) == 0 {
-		return "/* INSERT without VALUES */ DO 0", nil
+		return "", nil, nil
 	}
+	fieldsSql, err = commaFields(s.scope, fields)
+	return
+}
 
-	tableSql := s.scope.Tables[0].GetSQL(s.scope)
-	fieldsSql, err := commaFields(s.scope, fields)
+func (s insertStatus) buildSQL(args *argList) (string, error) {
+	s.scope.args = args
+	fieldsSql, values, err := s.getFieldsAndValues()
 	if err != nil {
 		return "", err
 	}
+
+	if len(values) == 0 {
+		return "/* INSERT without VALUES */ DO 0", nil
+	}
+
+	if s.batchSize > 0 && len(values) > s.batchSize {
+		if s.batch == nil {
+			return "", fmt.Errorf("the insert of %d rows is split into batches of %d rows, use Execute instead", len(values), s.batchSize)
+		}
+		start := *s.batch * s.batchSize
+		end := start + s.batchSize
+		if end > len(values) {
+			end = len(values)
+		}
+		values = values[start:end]
+	}
+
+	tableSql := s.scope.Tables[0].GetSQL(s.scope)
 	valuesSql, err := commaValues(s.scope, values)
 	if err != nil {
 		return "", err
 	}
 
-	sqlString := s.method + " INTO " + tableSql + " (" + fieldsSql + ") VALUES " + valuesSql
-	if len(s.onDuplicateKeyUpdateAssignments) > 0 {
+	method := s.method
+	if s.ignoreDuplicates {
+		if err := checkDialect(s.scope, "INSERT IGNORE", dialectMySQL, dialectSqlite3, dialectPostgres); err != nil {
+			return "", err
+		}
+		switch getDialect(s.scope) {
+		case dialectMySQL, dialectUnknown:
+			method += " IGNORE"
+		case dialectSqlite3:
+			method += " OR IGNORE"
+		case dialectPostgres:
+			s.onConflict = &onConflict{doNothing: true}
+		}
+	}
+
+	var sb strings.Builder
+	sb.Grow(128)
+	if err := appendRouteHint(&sb, s.routeHint); err != nil {
+		return "", err
+	}
+	sb.WriteString(method + " INTO " + tableSql + " (" + fieldsSql + ") VALUES " + valuesSql)
+	// an unknown dialect is rendered as MySQL, as by IgnoreDuplicates and Excluded
+	dialect := getDialect(s.scope)
+	asMySQL := dialect == dialectMySQL || dialect == dialectUnknown
+	if s.onConflict != nil && s.onConflict.where != nil {
+		if err := checkDialect(s.scope, "ON CONFLICT DO UPDATE WHERE", dialectSqlite3, dialectPostgres); err != nil {
+			return "", err
+		}
+		if asMySQL {
+			return "", errors.New("ON CONFLICT DO UPDATE WHERE is not supported by ON DUPLICATE KEY UPDATE")
+		}
+	}
+	if s.onConflict != nil && !asMySQL {
+		if err := s.appendOnConflict(&sb); err != nil {
+			return "", err
+		}
+	} else if s.onConflict != nil && s.onConflict.doNothing {
+		ignoredField := s.scope.Tables[0].GetFields()[0]
+		if len(s.onConflict.target) > 0 {
+			ignoredField = s.onConflict.target[0]
+		}
+		fieldSql, err := ignoredField.GetSQL(s.scope)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(" ON DUPLICATE KEY UPDATE " + fieldSql + " = " + fieldSql)
+	} else if len(s.onDuplicateKeyUpdateAssignments) > 0 {
 		assignmentsSql, err := commaAssignments(s.scope, s.onDuplicateKeyUpdateAssignments)
 		if err != nil {
 			return "", err
 		}
-		sqlString += " ON DUPLICATE KEY UPDATE " + assignmentsSql
+		sb.WriteString(" ON DUPLICATE KEY UPDATE " + assignmentsSql)
+	}
+	if err := appendReturning(&sb, s.scope, s.returning); err != nil {
+		return "", err
 	}
 
-	return sqlString, nil
+	return sb.String(), nil
+}
+
+func (s insertStatus) appendOnConflict(sb *strings.Builder) error {
+	if err := checkDialect(s.scope, "ON CONFLICT", dialectMySQL, dialectSqlite3, dialectPostgres); err != nil {
+		return err
+	}
+	sb.WriteString(" ON CONFLICT")
+	if len(s.onConflict.target) > 0 {
+		targetSql, err := commaFields(s.scope, s.onConflict.target)
+		if err != nil {
+			return err
+		}
+		sb.WriteString(" (" + targetSql + ")")
+	}
+	if s.onConflict.doNothing {
+		sb.WriteString(" DO NOTHING")
+		return nil
+	}
+	if len(s.onConflict.target) == 0 {
+		// only MySQL infers the conflicting key of an update
+		return errors.New("ON CONFLICT DO UPDATE requires a conflict target on PostgreSQL and SQLite")
+	}
+	assignmentsSql, err := commaAssignments(s.scope, s.onDuplicateKeyUpdateAssignments)
+	if err != nil {
+		return err
+	}
+	sb.WriteString(" DO UPDATE SET " + assignmentsSql)
+	if s.onConflict.where != nil {
+		// the columns are qualified, as they are ambiguous with those of the excluded row
+		whereScope := s.scope
+		whereScope.Tables = nil
+		whereSql, err := s.onConflict.where.GetSQL(whereScope)
+		if err != nil {
+			return err
+		}
+		sb.WriteString(" WHERE " + whereSql)
+	}
+	return nil
 }
 
 func (s insertStatus) WithContext(ctx context.Context) toInsertFinal {
@@ -199,10 +518,93 @@ func (s insertStatus) WithContext(ctx context.Context) toInsertFinal {
 	return s
 }
 
+// BatchSize splits the rows into batches of at most size rows, which are inserted by separate statements
+// within a transaction on Execute. The result sums up the affected rows of the batches.
+func (s insertStatus) BatchSize(size int) toInsertWithDuplicateKey {
+	s.batchSize = size
+	return s
+}
+
 func (s insertStatus) Execute() (result sql.Result, err error) {
-	sqlString, err := s.GetSQL()
+	if s.batchSize > 0 {
+		if s, err = s.prepare(); err != nil {
+			return nil, err
+		}
+		if len(s.prepared.values) > s.batchSize {
+			return s.executeBatches(len(s.prepared.values))
+		}
+	}
+	return s.scope.Database.executeStatement(s.ctx, s)
+}
+
+// Stats reports the length of the SQL, the number of bound arguments and the number of rows of the insert
+// as a single statement without executing it, e.g. for choosing a BatchSize within max_allowed_packet on MySQL
+// or the limit of 65535 parameters on PostgreSQL. The arguments are counted as they would be executed,
+// i.e. 0 unless parameterized queries are enabled.
+func (s insertStatus) Stats() (sqlLen int, paramCount int, rowCount int, err error) {
+	if s, err = s.prepare(); err != nil {
+		return
+	}
+	var args *argList
+	if s.scope.Database.parameterized {
+		args = &argList{}
+	}
+	s.batchSize = 0
+	sqlString, err := s.buildSQL(args)
 	if err != nil {
-		return nil, err
+		return
+	}
+	sqlLen = len(normalizeSpaces(sqlString, getDialect(s.scope)))
+	if args != nil {
+		paramCount = len(args.values)
+	}
+	return sqlLen, paramCount, len(s.prepared.values), nil
+}
+
+type batchResult struct {
+	lastInsertId    int64
+	lastInsertIdErr error
+	rowsAffected    int64
+}
+
+func (r batchResult) LastInsertId() (int64, error) {
+	return r.lastInsertId, r.lastInsertIdErr
+}
+
+func (r batchResult) RowsAffected() (int64, error) {
+	return r.rowsAffected, nil
+}
+
+func (s insertStatus) executeBatches(count int) (result sql.Result, err error) {
+	db := s.scope.Database
+	if db.tx == nil {
+		err = db.BeginTx(s.ctx, nil, func(tx Transaction) error {
+			s.scope.Database = tx.(*database)
+			result, err = s.executeBatches(count)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		return
+	}
+
+	var total batchResult
+	for batch := 0; batch*s.batchSize < count; batch++ {
+		s.batch = &batch
+		r, err := db.executeStatement(s.ctx, s)
+		if err != nil {
+			return nil, err
+		}
+		if batch == 0 {
+			// the id of the first inserted row, as MySQL reports for a multi-row insert
+			total.lastInsertId, total.lastInsertIdErr = r.LastInsertId()
+		}
+		rowsAffected, err := r.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		total.rowsAffected += rowsAffected
 	}
-	return s.scope.Database.ExecuteContext(s.ctx, sqlString)
+	return total, nil
 }
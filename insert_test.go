@@ -2,6 +2,7 @@ package sqlingo
 
 import (
 	"context"
+	"database/sql/driver"
 	"errors"
 	"testing"
 )
@@ -17,6 +18,10 @@ func (t tTest) GetFields() []Field {
 	return []Field{t.F1, t.F2}
 }
 
+func (t tTest) GetPrimaryKeyFields() []Field {
+	return []Field{t.F1}
+}
+
 type fTestF1 struct{ NumberField }
 type fTestF2 struct{ StringField }
 
@@ -154,3 +159,210 @@ func TestInsert(t *testing.T) {
 		t.Error("should get error here")
 	}
 }
+
+func TestInsertRows(t *testing.T) {
+	db := newMockDatabase()
+
+	_, _ = db.InsertInto(Test).Rows([]map[string]interface{}{
+		{"f2": "a", "f1": 1},
+		{"f1": 2},
+	}).Execute()
+	assertLastSql(t, "INSERT INTO `test` (`f1`, `f2`) VALUES (1, 'a'), (2, DEFAULT)")
+
+	_, _ = db.InsertInto(Test).Rows([]map[string]interface{}{
+		{"f2": "a"},
+	}).OnDuplicateKeyUpdate().Set(Test.F2, "b").Execute()
+	assertLastSql(t, "INSERT INTO `test` (`f2`) VALUES ('a') ON DUPLICATE KEY UPDATE `f2` = 'b'")
+
+	if _, err := db.InsertInto(Test).Rows([]map[string]interface{}{{"f3": 1}}).Execute(); err == nil {
+		t.Error("should fail on unknown column")
+	}
+
+	db.(*database).dialect = dialectSqlite3
+	_, _ = db.InsertInto(Test).Rows([]map[string]interface{}{
+		{"f2": "a"},
+		{"f1": 2},
+	}).Execute()
+	assertLastSql(t, `INSERT INTO "test" ("f1", "f2") VALUES (NULL, 'a'), (2, NULL)`)
+}
+
+func TestInsertOnConflict(t *testing.T) {
+	db := newMockDatabase()
+
+	_, _ = db.InsertInto(Test).Fields(Test.F1, Test.F2).Values(1, "a").
+		OnConflict(Test.F1).DoUpdateSet(Test.F2, "b").DoUpdateSet(Test.F1, 2).Execute()
+	assertLastSql(t, "INSERT INTO `test` (`f1`, `f2`) VALUES (1, 'a') ON DUPLICATE KEY UPDATE `f2` = 'b', `f1` = 2")
+	_, _ = db.InsertInto(Test).Fields(Test.F1, Test.F2).Values(1, "a").OnConflict(Test.F2).DoNothing().Execute()
+	assertLastSql(t, "INSERT INTO `test` (`f1`, `f2`) VALUES (1, 'a') ON DUPLICATE KEY UPDATE `f2` = `f2`")
+
+	db.(*database).dialect = dialectPostgres
+	_, _ = db.InsertInto(Test).Fields(Test.F1, Test.F2).Values(1, "a").
+		OnConflict(Test.F1).DoUpdateSet(Test.F2, Raw("EXCLUDED.f2")).Execute()
+	assertLastSql(t, `INSERT INTO "test" ("f1", "f2") VALUES (1, 'a') ON CONFLICT ("f1") DO UPDATE SET "f2" = EXCLUDED.f2`)
+	_, _ = db.InsertInto(Test).Fields(Test.F1, Test.F2).Values(1, "a").OnConflict().DoNothing().Execute()
+	assertLastSql(t, `INSERT INTO "test" ("f1", "f2") VALUES (1, 'a') ON CONFLICT DO NOTHING`)
+	if _, err := db.InsertInto(Test).Fields(Test.F1, Test.F2).Values(1, "a").OnConflict().DoUpdateSet(Test.F2, "b").Execute(); err == nil {
+		t.Error("should fail without a conflict target")
+	}
+
+	db.(*database).dialect = dialectSqlite3
+	if _, err := db.InsertInto(Test).Fields(Test.F1, Test.F2).Values(1, "a").OnConflict().DoUpdateSet(Test.F2, "b").Execute(); err == nil {
+		t.Error("should fail without a conflict target")
+	}
+	_, _ = db.InsertInto(Test).Fields(Test.F1, Test.F2).Values(1, "a").OnConflict(Test.F1, Test.F2).DoNothing().Execute()
+	assertLastSql(t, `INSERT INTO "test" ("f1", "f2") VALUES (1, 'a') ON CONFLICT ("f1", "f2") DO NOTHING`)
+
+	_, _ = db.InsertInto(Test).Fields(Test.F1, Test.F2).Values(1, "a").OnConflict(Test.F1).DoUpdateSet(Test.F2, Excluded(Test.F2)).Execute()
+	assertLastSql(t, `INSERT INTO "test" ("f1", "f2") VALUES (1, 'a') ON CONFLICT ("f1") DO UPDATE SET "f2" = excluded."f2"`)
+	assertValue(t, Excluded(Test.F2), "VALUES(`f2`)")
+
+	_, _ = db.InsertInto(Test).Fields(Test.F1, Test.F2).Values(1, "a").OnConflict(Test.F1).
+		DoUpdateSet(Test.F2, Excluded(Test.F2)).Where(Excluded(Test.F2).GreaterThan(Test.F2)).Execute()
+	assertLastSql(t, `INSERT INTO "test" ("f1", "f2") VALUES (1, 'a') ON CONFLICT ("f1") DO UPDATE SET "f2" = excluded."f2" `+
+		`WHERE excluded."f2" > "test"."f2"`)
+
+	// MySQL infers the conflicting key
+	db.(*database).dialect = dialectMySQL
+	_, _ = db.InsertInto(Test).Fields(Test.F1, Test.F2).Values(1, "a").OnConflict().DoUpdateSet(Test.F2, "b").Execute()
+	assertLastSql(t, "INSERT INTO `test` (`f1`, `f2`) VALUES (1, 'a') ON DUPLICATE KEY UPDATE `f2` = 'b'")
+	if _, err := db.InsertInto(Test).Fields(Test.F1, Test.F2).Values(1, "a").OnConflict(Test.F1).
+		DoUpdateSet(Test.F2, "b").Where(Test.F2.Equals("a")).Execute(); err == nil {
+		t.Error("should fail on MySQL")
+	}
+
+	// an unknown dialect is rendered as MySQL throughout
+	db.(*database).dialect = dialectUnknown
+	_, _ = db.InsertInto(Test).Fields(Test.F1, Test.F2).Values(1, "a").
+		OnConflict(Test.F1).DoUpdateSet(Test.F2, Excluded(Test.F2)).Execute()
+	assertLastSql(t, `INSERT INTO "test" ("f1", "f2") VALUES (1, 'a') ON DUPLICATE KEY UPDATE "f2" = VALUES("f2")`)
+	if _, err := db.InsertInto(Test).Fields(Test.F1, Test.F2).Values(1, "a").OnConflict(Test.F1).
+		DoUpdateSet(Test.F2, "b").Where(Test.F2.Equals("a")).Execute(); err == nil {
+		t.Error("should fail without the dialect")
+	}
+
+	db.(*database).dialect = dialectMSSQL
+	if _, err := db.InsertInto(Test).Fields(Test.F1, Test.F2).Values(1, "a").OnConflict(Test.F1).DoNothing().Execute(); err == nil {
+		t.Error("should fail on SQL Server")
+	}
+}
+
+func TestInsertIgnoreDuplicates(t *testing.T) {
+	db := newMockDatabase()
+
+	_, _ = db.InsertInto(Test).Fields(Test.F1, Test.F2).Values(1, "a").IgnoreDuplicates().Execute()
+	assertLastSql(t, "INSERT IGNORE INTO `test` (`f1`, `f2`) VALUES (1, 'a')")
+
+	db.(*database).dialect = dialectSqlite3
+	_, _ = db.InsertInto(Test).Fields(Test.F1, Test.F2).Values(1, "a").IgnoreDuplicates().Execute()
+	assertLastSql(t, `INSERT OR IGNORE INTO "test" ("f1", "f2") VALUES (1, 'a')`)
+
+	db.(*database).dialect = dialectPostgres
+	_, _ = db.InsertInto(Test).Fields(Test.F1, Test.F2).Values(1, "a").IgnoreDuplicates().Execute()
+	assertLastSql(t, `INSERT INTO "test" ("f1", "f2") VALUES (1, 'a') ON CONFLICT DO NOTHING`)
+
+	db.(*database).dialect = dialectMSSQL
+	if _, err := db.InsertInto(Test).Fields(Test.F1, Test.F2).Values(1, "a").IgnoreDuplicates().Execute(); err == nil {
+		t.Error("should fail on SQL Server")
+	}
+}
+
+func TestInsertBatchSize(t *testing.T) {
+	db := newMockDatabase()
+	sharedMockConn.execResult = driver.RowsAffected(1)
+	defer func() {
+		sharedMockConn.execResult = nil
+	}()
+	var sqls []string
+	db.SetInterceptor(func(ctx context.Context, sql string, invoker InvokerFunc) error {
+		sqls = append(sqls, sql)
+		return invoker(ctx, sql)
+	})
+
+	models := []TestModel{{1, "a"}, {2, "b"}, {3, "c"}, {4, "d"}, {5, "e"}}
+	result, err := db.InsertInto(Test).Models(models).BatchSize(2).Execute()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected != 3 {
+		t.Error(rowsAffected)
+	}
+	if len(sqls) != 3 {
+		t.Fatal(sqls)
+	}
+	assertEqual(t, sqls[0], "INSERT INTO `test` (`f1`, `f2`) VALUES (1, 'a'), (2, 'b')")
+	assertEqual(t, sqls[1], "INSERT INTO `test` (`f1`, `f2`) VALUES (3, 'c'), (4, 'd')")
+	assertEqual(t, sqls[2], "INSERT INTO `test` (`f1`, `f2`) VALUES (5, 'e')")
+
+	if _, err := db.InsertInto(Test).Models(models).BatchSize(2).GetSQL(); err == nil {
+		t.Error("should fail for batched insert")
+	}
+	sqlString, err := db.InsertInto(Test).Models(models).IgnoreDuplicates().BatchSize(5).GetSQL()
+	if err != nil {
+		t.Error(err)
+	}
+	assertEqual(t, sqlString, "INSERT IGNORE INTO `test` (`f1`, `f2`) VALUES (1, 'a'), (2, 'b'), (3, 'c'), (4, 'd'), (5, 'e')")
+
+	// the values of the models are taken once for all batches
+	counted := make([]countingModel, 5)
+	calls := 0
+	for i := range counted {
+		counted[i] = countingModel{TestModel: models[i], calls: &calls}
+	}
+	if _, err := db.InsertInto(Test).Models(counted).BatchSize(2).Execute(); err != nil {
+		t.Error(err)
+	}
+	if calls != 5 {
+		t.Error(calls)
+	}
+}
+
+// countingModel counts the calls of GetValues.
+type countingModel struct {
+	TestModel
+	calls *int
+}
+
+func (m countingModel) GetValues() []interface{} {
+	*m.calls++
+	return m.TestModel.GetValues()
+}
+
+func TestInsertStats(t *testing.T) {
+	db := newMockDatabase()
+	models := []TestModel{{1, "a"}, {2, "b"}, {3, "c"}}
+
+	// measured as a single statement regardless of the batch size, and not executed
+	sharedMockConn.lastSql = ""
+	sqlLen, paramCount, rowCount, err := db.InsertInto(Test).Models(models).BatchSize(2).Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedSql := "INSERT INTO `test` (`f1`, `f2`) VALUES (1, 'a'), (2, 'b'), (3, 'c')"
+	if sqlLen != len(expectedSql) || paramCount != 0 || rowCount != 3 {
+		t.Error(sqlLen, paramCount, rowCount)
+	}
+	assertLastSql(t, "")
+
+	db.EnableParameterizedQuery(true)
+	sqlLen, paramCount, rowCount, err = db.InsertInto(Test).Fields(Test.F1, Test.F2).Values(1, "a").Values(2, "b").Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sqlLen != len("INSERT INTO `test` (`f1`, `f2`) VALUES (?, ?), (?, ?)") || paramCount != 4 || rowCount != 2 {
+		t.Error(sqlLen, paramCount, rowCount)
+	}
+
+	// the values are built once for the length and the count of rows
+	calls := 0
+	counted := []countingModel{{TestModel: models[0], calls: &calls}, {TestModel: models[1], calls: &calls}}
+	if _, _, rowCount, err := db.InsertInto(Test).Models(counted).Stats(); err != nil || rowCount != 2 {
+		t.Error(rowCount, err)
+	}
+	if calls != 2 {
+		t.Error(calls)
+	}
+
+	if _, _, _, err := db.InsertInto(Test).Fields(Test.F1).Values(errorExpression(errors.New("error"))).Stats(); err == nil {
+		t.Error("should get error here")
+	}
+}
@@ -0,0 +1,150 @@
+package sqlingo
+
+import (
+	"strconv"
+	"strings"
+)
+
+// JSONPath is a path into a JSON value, built by chaining Get and Index and ended by a typed expression.
+type JSONPath interface {
+	Get(key string) JSONPath
+	Index(index int) JSONPath
+	AsString() StringExpression
+	AsNumber() NumberExpression
+	AsJSON() UnknownExpression
+}
+
+type jsonPathStep struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+type jsonPath struct {
+	target Expression
+	steps  []jsonPathStep
+}
+
+func (e expression) JSON() JSONPath {
+	return jsonPath{target: e}
+}
+
+func (p jsonPath) withStep(step jsonPathStep) jsonPath {
+	steps := make([]jsonPathStep, len(p.steps), len(p.steps)+1)
+	copy(steps, p.steps)
+	p.steps = append(steps, step)
+	return p
+}
+
+// Get navigates to the member with the specified key.
+func (p jsonPath) Get(key string) JSONPath {
+	return p.withStep(jsonPathStep{key: key})
+}
+
+// Index navigates to the array element with the specified zero-based index.
+func (p jsonPath) Index(index int) JSONPath {
+	return p.withStep(jsonPathStep{index: index, isIndex: true})
+}
+
+// AsString extracts the value as unquoted text.
+func (p jsonPath) AsString() StringExpression {
+	return p.extract(jsonText)
+}
+
+// AsNumber extracts the value as a number.
+func (p jsonPath) AsNumber() NumberExpression {
+	return p.extract(jsonNumber)
+}
+
+// AsJSON extracts the value as JSON.
+func (p jsonPath) AsJSON() UnknownExpression {
+	return p.extract(jsonValue)
+}
+
+// pathString returns the path in the "$.key[index]" syntax shared by MySQL, SQLite and SQL Server.
+// A key that is not a plain identifier is quoted, e.g. $."first name".
+func (p jsonPath) pathString() string {
+	var sb strings.Builder
+	sb.WriteString("$")
+	for _, step := range p.steps {
+		if step.isIndex {
+			sb.WriteString("[" + strconv.Itoa(step.index) + "]")
+			continue
+		}
+		if isPlainIdentifier(step.key) {
+			sb.WriteString("." + step.key)
+			continue
+		}
+		sb.WriteString(".\"")
+		for i := 0; i < len(step.key); i++ {
+			if step.key[i] == '"' || step.key[i] == '\\' {
+				sb.WriteByte('\\')
+			}
+			sb.WriteByte(step.key[i])
+		}
+		sb.WriteString("\"")
+	}
+	return sb.String()
+}
+
+type jsonResultType int
+
+const (
+	jsonValue jsonResultType = iota
+	jsonText
+	jsonNumber
+)
+
+func (p jsonPath) extract(resultType jsonResultType) expression {
+	return expression{builder: func(scope scope) (string, error) {
+		targetSql, targetPriority, err := getSQL(scope, p.target)
+		if err != nil {
+			return "", err
+		}
+		dialect := getDialect(scope)
+		path := quoteDialectString(dialect, p.pathString())
+
+		switch dialect {
+		case dialectPostgres:
+			if targetPriority > 0 {
+				targetSql = "(" + targetSql + ")"
+			}
+			var sb strings.Builder
+			sb.WriteString(targetSql)
+			for i, step := range p.steps {
+				if resultType != jsonValue && i == len(p.steps)-1 {
+					sb.WriteString("->>")
+				} else {
+					sb.WriteString("->")
+				}
+				if step.isIndex {
+					sb.WriteString(strconv.Itoa(step.index))
+				} else {
+					sb.WriteString(quoteDialectString(dialect, step.key))
+				}
+			}
+			if resultType == jsonNumber {
+				return "CAST(" + sb.String() + " AS NUMERIC)", nil
+			}
+			return sb.String(), nil
+		case dialectSqlite3:
+			// json_extract returns SQL text and numbers for scalar values
+			return "json_extract(" + targetSql + ", " + path + ")", nil
+		case dialectMSSQL:
+			switch resultType {
+			case jsonText:
+				return "JSON_VALUE(" + targetSql + ", " + path + ")", nil
+			case jsonNumber:
+				return "CAST(JSON_VALUE(" + targetSql + ", " + path + ") AS FLOAT)", nil
+			default:
+				return "JSON_QUERY(" + targetSql + ", " + path + ")", nil
+			}
+		default:
+			sql := "JSON_EXTRACT(" + targetSql + ", " + path + ")"
+			if resultType == jsonText {
+				sql = "JSON_UNQUOTE(" + sql + ")"
+			}
+			return sql, nil
+		}
+	}, priority: 10} // the PostgreSQL -> and ->> operators share the class of & and |, of which | binds the loosest
+}
@@ -0,0 +1,37 @@
+package sqlingo
+
+import "testing"
+
+func TestJSONPath(t *testing.T) {
+	e := expression{sql: "<>"}
+	path := e.JSON().Get("address").Get("city")
+
+	assertValue(t, path.AsString(), "JSON_UNQUOTE(JSON_EXTRACT(<>, '$.address.city'))")
+	assertValue(t, e.JSON().Get("tags").Index(0).AsJSON(), "JSON_EXTRACT(<>, '$.tags[0]')")
+	assertValue(t, e.JSON().Get("age").AsNumber().Add(1), "(JSON_EXTRACT(<>, '$.age')) + 1")
+
+	assertDialectValue(t, dialectPostgres, path.AsString(), "<>->'address'->>'city'")
+	assertDialectValue(t, dialectPostgres, path.AsJSON(), "<>->'address'->'city'")
+	assertDialectValue(t, dialectPostgres, e.JSON().Get("tags").Index(1).AsString().Equals("a"), "<>->'tags'->>1 = 'a'")
+	assertDialectValue(t, dialectPostgres, e.JSON().Get("age").AsNumber(), "CAST(<>->>'age' AS NUMERIC)")
+	assertDialectValue(t, dialectPostgres, e.Add(1).JSON().Get("a").AsJSON(), "(<> + 1)->'a'")
+	// the operators of the class are evaluated from left to right
+	assertDialectValue(t, dialectPostgres, expression{sql: "b"}.BitAnd(e.JSON().Get("a").AsJSON()), "b & (<>->'a')")
+	assertDialectValue(t, dialectPostgres, e.JSON().Get("a").AsJSON().BitOr(1), "<>->'a' | 1")
+
+	assertDialectValue(t, dialectSqlite3, path.AsString(), "json_extract(<>, '$.address.city')")
+	assertDialectValue(t, dialectMSSQL, path.AsString(), "JSON_VALUE(<>, '$.address.city')")
+	assertDialectValue(t, dialectMSSQL, path.AsJSON(), "JSON_QUERY(<>, '$.address.city')")
+
+	// keys other than plain identifiers are quoted in the path, and the literal is quoted per dialect
+	odd := e.JSON().Get("first name").Get("it's")
+	assertValue(t, odd.AsJSON(), `JSON_EXTRACT(<>, '$.\"first name\".\"it\'s\"')`)
+	assertDialectValue(t, dialectSqlite3, odd.AsJSON(), `json_extract(<>, '$."first name"."it''s"')`)
+	assertDialectValue(t, dialectMSSQL, odd.AsString(), `JSON_VALUE(<>, '$."first name"."it''s"')`)
+	assertDialectValue(t, dialectPostgres, odd.AsJSON(), `<>->'first name'->'it''s'`)
+
+	// paths are immutable
+	base := e.JSON().Get("a")
+	_ = base.Get("b")
+	assertValue(t, base.Get("c").AsJSON(), "JSON_EXTRACT(<>, '$.a.c')")
+}
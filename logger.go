@@ -0,0 +1,60 @@
+package sqlingo
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Logger is the interface of a query logger. LogQuery is called after each query or statement is executed.
+type Logger interface {
+	LogQuery(ctx context.Context, sql string, args []interface{}, duration time.Duration, err error)
+}
+
+type noopLogger struct{}
+
+func (noopLogger) LogQuery(ctx context.Context, sql string, args []interface{}, duration time.Duration, err error) {
+}
+
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// NewSlogLogger creates a Logger which writes to the slog logger, or to the default slog logger if it is nil.
+// Successful queries are logged at debug level and failed ones at error level.
+func NewSlogLogger(logger *slog.Logger) Logger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return slogLogger{logger: logger}
+}
+
+func (l slogLogger) LogQuery(ctx context.Context, sql string, args []interface{}, duration time.Duration, err error) {
+	attrs := []slog.Attr{
+		slog.String("sql", sql),
+		slog.Duration("duration", duration),
+	}
+	if len(args) > 0 {
+		attrs = append(attrs, slog.Any("args", args))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+		l.logger.LogAttrs(ctx, slog.LevelError, "sqlingo query failed", attrs...)
+		return
+	}
+	l.logger.LogAttrs(ctx, slog.LevelDebug, "sqlingo query", attrs...)
+}
+
+// SetQueryLogger sets the query logger. A nil logger disables query logging, which is the default.
+func (d *database) SetQueryLogger(logger Logger) {
+	if logger == nil {
+		logger = noopLogger{}
+	}
+	d.queryLogger = logger
+}
+
+func (d database) logQuery(ctx context.Context, sql string, args []interface{}, duration time.Duration, err error) {
+	if d.queryLogger != nil {
+		d.queryLogger.LogQuery(ctx, sql, args, duration, err)
+	}
+}
@@ -0,0 +1,52 @@
+package sqlingo
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+type recordingLogger struct {
+	sqls []string
+}
+
+func (l *recordingLogger) LogQuery(ctx context.Context, sql string, args []interface{}, duration time.Duration, err error) {
+	l.sqls = append(l.sqls, sql)
+}
+
+func TestQueryLogger(t *testing.T) {
+	db := newMockDatabase()
+	logger := &recordingLogger{}
+	db.SetQueryLogger(logger)
+
+	_, _ = db.Select(1).FetchAll()
+	_, _ = db.Execute("DELETE FROM t")
+	if len(logger.sqls) != 2 || logger.sqls[0] != "SELECT 1" || logger.sqls[1] != "DELETE FROM t" {
+		t.Errorf("logged %v", logger.sqls)
+	}
+
+	db.SetQueryLogger(nil)
+	_, _ = db.Select(1).FetchAll()
+	if len(logger.sqls) != 2 {
+		t.Error("logger should be removed")
+	}
+}
+
+func TestSlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewSlogLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	logger.LogQuery(context.Background(), "SELECT 1", nil, time.Millisecond, nil)
+	if line := buf.String(); !strings.Contains(line, "level=DEBUG") || !strings.Contains(line, `sql="SELECT 1"`) {
+		t.Errorf("unexpected log %s", line)
+	}
+
+	buf.Reset()
+	logger.LogQuery(context.Background(), "SELECT 1", []interface{}{1}, time.Millisecond, context.Canceled)
+	if line := buf.String(); !strings.Contains(line, "level=ERROR") || !strings.Contains(line, "error=") || !strings.Contains(line, "args=") {
+		t.Errorf("unexpected log %s", line)
+	}
+}
@@ -1,13 +1,40 @@
 package sqlingo
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 // OrderBy indicates the ORDER BY column and the status of descending order.
 type OrderBy interface {
 	GetSQL(scope scope) (string, error)
 }
 
+// SortOrder is an order by an expression in the specified direction, whose placement of NULLs can be set.
+type SortOrder interface {
+	OrderBy
+	NullsFirst() OrderBy
+	NullsLast() OrderBy
+}
+
 type orderBy struct {
-	by   Expression
-	desc bool
+	by    Expression
+	desc  bool
+	nulls string // FIRST or LAST, empty for the default of the database
+}
+
+// NullsFirst places the NULLs before the other values, i.e. NULLS FIRST on PostgreSQL and SQLite.
+// It is emulated by ordering by IS NULL first on MySQL and by a CASE expression on SQL Server.
+func (o orderBy) NullsFirst() OrderBy {
+	o.nulls = "FIRST"
+	return o
+}
+
+// NullsLast places the NULLs after the other values. See NullsFirst.
+func (o orderBy) NullsLast() OrderBy {
+	o.nulls = "LAST"
+	return o
 }
 
 func (o orderBy) GetSQL(scope scope) (string, error) {
@@ -17,6 +44,63 @@ func (o orderBy) GetSQL(scope scope) (string, error) {
 	}
 	if o.desc {
 		sql += " DESC"
+	} else if o.nulls != "" {
+		sql += " ASC"
+	}
+	if o.nulls == "" {
+		return sql, nil
+	}
+
+	switch getDialect(scope) {
+	case dialectPostgres, dialectSqlite3:
+		return sql + " NULLS " + o.nulls, nil
+	}
+	isNullSql, err := o.by.IsNull().GetSQL(scope)
+	if err != nil {
+		return "", err
+	}
+	if getDialect(scope) == dialectMSSQL {
+		nullRank := "0 ELSE 1"
+		if o.nulls == "LAST" {
+			nullRank = "1 ELSE 0"
+		}
+		return "CASE WHEN " + isNullSql + " THEN " + nullRank + " END, " + sql, nil
+	}
+	if o.nulls == "FIRST" {
+		isNullSql += " DESC"
+	}
+	return isNullSql + ", " + sql, nil
+}
+
+// OrderByOrdinal creates the ascending order by the n-th selected column counting from 1, i.e. ORDER BY n,
+// e.g. for ordering the result of a union.
+func OrderByOrdinal(n int) OrderBy {
+	if n < 1 {
+		return orderBy{by: errorExpression(fmt.Errorf("invalid order column ordinal %d", n))}
+	}
+	return orderBy{by: staticExpression(strconv.Itoa(n), 0, false)}
+}
+
+// SafeOrderBy creates the order by a column chosen by name, e.g. from user input, which must be the name of
+// one of the allowed fields. The direction is either ASC or DESC in any case, or empty for ascending order.
+// An error is returned for any other column or direction, so the input never reaches the SQL.
+func SafeOrderBy(name string, allowed []Field, dir string) (OrderBy, error) {
+	var desc bool
+	switch strings.ToUpper(dir) {
+	case "", "ASC":
+	case "DESC":
+		desc = true
+	default:
+		return nil, fmt.Errorf("invalid order direction %q", dir)
+	}
+	for _, field := range allowed {
+		fieldName, err := getFieldName(field)
+		if err != nil {
+			return nil, err
+		}
+		if fieldName == name {
+			return orderBy{by: field, desc: desc}, nil
+		}
 	}
-	return sql, nil
+	return nil, fmt.Errorf("invalid order column %q", name)
 }
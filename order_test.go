@@ -13,3 +13,67 @@ func TestOrder(t *testing.T) {
 		return "", errors.New("error")
 	}}})
 }
+
+func TestOrderByOrdinal(t *testing.T) {
+	assertValue(t, OrderByOrdinal(2), "2")
+	assertError(t, OrderByOrdinal(0))
+
+	db := newMockDatabase()
+	_, _ = db.Select(field1, field2.Sum()).From(table1).GroupBy(field1).
+		OrderBy(OrderByOrdinal(2), field2.Sum().Add(field1).Desc()).FetchAll()
+	assertLastSql(t, "SELECT `field1`, SUM(`field2`) FROM `table1` GROUP BY `field1` ORDER BY 2, SUM(`field2`) + `field1` DESC")
+
+	_, _ = db.Select(field1, field2).From(table1).
+		UnionAll(db.Select(field3, field3.Count()).From(table2).GroupBy(field3)).
+		OrderBy(OrderByOrdinal(2), field1.Desc()).FetchAll()
+	assertLastSql(t, "SELECT `field1`, `field2` FROM `table1` "+
+		"UNION ALL (SELECT `field3`, COUNT(`field3`) FROM `table2` GROUP BY `field3`) ORDER BY 2, `field1` DESC")
+}
+
+func TestNullsOrder(t *testing.T) {
+	e := expression{sql: "x"}
+	assertValue(t, e.Asc(), "x")
+	assertValue(t, e.Asc().NullsLast(), "x IS NULL, x ASC")
+	assertValue(t, e.Asc().NullsFirst(), "x IS NULL DESC, x ASC")
+	assertValue(t, e.Desc().NullsLast(), "x IS NULL, x DESC")
+	assertValue(t, e.Add(1).Desc().NullsFirst(), "x + 1 IS NULL DESC, x + 1 DESC")
+	assertDialectValue(t, dialectPostgres, e.Asc().NullsLast(), "x ASC NULLS LAST")
+	assertDialectValue(t, dialectSqlite3, e.Desc().NullsFirst(), "x DESC NULLS FIRST")
+	assertDialectValue(t, dialectMSSQL, e.Asc().NullsLast(), "CASE WHEN x IS NULL THEN 1 ELSE 0 END, x ASC")
+	assertDialectValue(t, dialectMSSQL, e.Desc().NullsFirst(), "CASE WHEN x IS NULL THEN 0 ELSE 1 END, x DESC")
+
+	db := newMockDatabase()
+	_, _ = db.SelectFrom(table1).OrderBy(field1.Asc().NullsLast(), field2.Desc()).FetchAll()
+	assertLastSql(t, "SELECT * FROM `table1` ORDER BY `field1` IS NULL, `field1` ASC, `field2` DESC")
+}
+
+func TestSafeOrderBy(t *testing.T) {
+	allowed := []Field{field1, field2}
+
+	o, err := SafeOrderBy("field2", allowed, "desc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertValue(t, o, "`table1`.`field2` DESC")
+	o, err = SafeOrderBy("field1", allowed, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertValue(t, o, "`table1`.`field1`")
+
+	db := newMockDatabase()
+	_, _ = db.SelectFrom(table1).OrderBy(o).FetchAll()
+	assertLastSql(t, "SELECT * FROM `table1` ORDER BY `field1`")
+
+	for _, test := range []struct{ name, dir string }{
+		{"field3", "ASC"},
+		{"field1; DROP TABLE table1", ""},
+		{"`field1`", ""},
+		{"field1", "DESC; --"},
+		{"field1", "random"},
+	} {
+		if _, err := SafeOrderBy(test.name, allowed, test.dir); err == nil {
+			t.Error("should get error here", test)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package sqlingo
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// argList collects the bound arguments of a parameterized statement while its SQL is built.
+type argList struct {
+	values []interface{}
+}
+
+// add appends the argument and returns its placeholder in the dialect.
+func (a *argList) add(dialect dialect, value interface{}) string {
+	a.values = append(a.values, value)
+	switch dialect {
+	case dialectPostgres:
+		return "$" + strconv.Itoa(len(a.values))
+	case dialectMSSQL:
+		return "@p" + strconv.Itoa(len(a.values))
+	default:
+		return "?"
+	}
+}
+
+// postgresArray binds a list of values as a single PostgreSQL array in its text form, e.g. {1,2,3},
+// which is accepted by any driver without its own array type.
+type postgresArray []interface{}
+
+// newPostgresArray creates the array of the values if all of them are numbers, booleans or strings,
+// otherwise the values are bound one by one.
+func newPostgresArray(values []interface{}) (postgresArray, bool) {
+	array := make(postgresArray, len(values))
+	for i, value := range values {
+		arg, ok := getArg(value)
+		if !ok {
+			return nil, false
+		}
+		switch arg.(type) {
+		case int, int64, uint64, float64, bool, string:
+			array[i] = arg
+		default:
+			return nil, false
+		}
+	}
+	return array, true
+}
+
+func (a postgresArray) Value() (driver.Value, error) {
+	var sb strings.Builder
+	sb.WriteByte('{')
+	for i, element := range a {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		switch element := element.(type) {
+		case string:
+			sb.WriteByte('"')
+			sb.WriteString(strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(element))
+			sb.WriteByte('"')
+		case bool:
+			if element {
+				sb.WriteString("t")
+			} else {
+				sb.WriteString("f")
+			}
+		default:
+			sb.WriteString(fmt.Sprint(element))
+		}
+	}
+	sb.WriteByte('}')
+	return sb.String(), nil
+}
+
+// getArg returns the value to bind if the value is a literal that can be sent as an argument.
+// NULL, expressions, subqueries and lists are rendered as usual.
+func getArg(value interface{}) (interface{}, bool) {
+	switch value := value.(type) {
+	case nil, Expression, Assignment, toSelectFinal, toUpdateFinal, Table, CaseExpression, SQLValuer:
+		return nil, false
+	case int, string:
+		return value, true
+	case []byte:
+		return value, value != nil
+	case driver.Valuer:
+		if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr && v.IsNil() {
+			return nil, false
+		}
+		// the valuer is bound as is unless its value is NULL
+		if driverValue, err := value.Value(); err != nil || driverValue == nil {
+			return nil, false
+		}
+		return value, true
+	case time.Time:
+		return value, !value.IsZero()
+	case *time.Time:
+		if value == nil || value.IsZero() {
+			return nil, false
+		}
+		return *value, true
+	case *big.Int, big.Int, *big.Float, big.Float, *big.Rat, big.Rat:
+		// inlined as exact numeric literals
+		return nil, false
+	}
+
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint(), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.String:
+		return v.String(), true
+	case reflect.Array, reflect.Slice:
+		return nil, false
+	}
+	switch value := v.Interface().(type) {
+	case time.Time:
+		return value, !value.IsZero()
+	case interface{ String() string }:
+		return value.String(), true
+	}
+	return nil, false
+}
+
+// parameterizedStatement is a statement that can be built either with inlined values or with bound arguments.
+type parameterizedStatement interface {
+	GetSQL() (string, error)
+	GetSQLWithArgs() (string, []interface{}, error)
+}
+
+// buildSQLWithArgs builds the SQL of the statement, collecting the arguments into args if it is not nil.
+type buildSQLWithArgs interface {
+	buildSQL(args *argList) (string, error)
+}
+
+// getStatementSQL builds a nested statement, e.g. a subquery, sharing the arguments of the outer statement.
+func getStatementSQL(args *argList, statement interface{ GetSQL() (string, error) }) (string, error) {
+	if builder, ok := statement.(buildSQLWithArgs); ok && args != nil {
+		return builder.buildSQL(args)
+	}
+	return statement.GetSQL()
+}
+
+func (d database) executeStatement(ctx context.Context, statement parameterizedStatement) (sql.Result, error) {
+	if !d.parameterized {
+		sqlString, err := statement.GetSQL()
+		if err != nil {
+			return nil, err
+		}
+		return d.ExecuteContext(ctx, sqlString)
+	}
+	sqlString, args, err := statement.GetSQLWithArgs()
+	if err != nil {
+		return nil, err
+	}
+	return d.executeContext(ctx, sqlString, args)
+}
+
+func (d database) queryStatement(ctx context.Context, statement parameterizedStatement) (Cursor, error) {
+	if !d.parameterized {
+		sqlString, err := statement.GetSQL()
+		if err != nil {
+			return nil, err
+		}
+		return d.QueryContext(ctx, sqlString)
+	}
+	sqlString, args, err := statement.GetSQLWithArgs()
+	if err != nil {
+		return nil, err
+	}
+	return d.queryContext(ctx, sqlString, args)
+}
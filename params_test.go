@@ -0,0 +1,139 @@
+package sqlingo
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func assertSQLWithArgs(t *testing.T, statement parameterizedStatement, expectedSql string, expectedArgs ...interface{}) {
+	t.Helper()
+	sqlString, args, err := statement.GetSQLWithArgs()
+	if err != nil {
+		t.Error(err)
+	}
+	if sqlString != expectedSql {
+		t.Errorf("sql [%s] expected [%s]", sqlString, expectedSql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("args %v expected %v", args, expectedArgs)
+	}
+}
+
+type argsLogger struct {
+	args []interface{}
+}
+
+func (l *argsLogger) LogQuery(_ context.Context, _ string, args []interface{}, _ time.Duration, _ error) {
+	l.args = args
+}
+
+func TestGetSQLWithArgs(t *testing.T) {
+	db := newMockDatabase()
+	tm := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	assertSQLWithArgs(t, db.SelectFrom(table1).Where(field1.Equals(1), field2.In("a", CustomString("b")), field1.IsNull()).Limit(10),
+		"SELECT * FROM `table1` WHERE `field1` = ? AND `field2` IN (?, ?) AND `field1` IS NULL LIMIT 10",
+		1, "a", "b")
+	assertSQLWithArgs(t, db.Select(field1).From(table1).Where(field1.In(db.Select(field3).From(table2).Where(field3.GreaterThan(CustomInt(5))))).
+		UnionAll(db.Select(field3).From(table2).Where(field3.Between(true, 2.5))),
+		"SELECT `field1` FROM `table1` WHERE `field1` IN (SELECT `field3` FROM `table2` WHERE `field3` > ?) "+
+			"UNION ALL (SELECT `field3` FROM `table2` WHERE `field3` BETWEEN ? AND ?)",
+		int64(5), true, 2.5)
+	assertSQLWithArgs(t, db.InsertInto(Test).Fields(Test.F1, Test.F2).Values(1, nil).Values(2, &tm),
+		"INSERT INTO `test` (`f1`, `f2`) VALUES (?, NULL), (?, ?)", 1, 2, tm)
+	assertSQLWithArgs(t, db.Update(table1).Set(field1, field1.Add(1)).Set(field2, "x").Where(field1.NotEquals(uint8(3))),
+		"UPDATE `table1` SET `field1` = `field1` + ?, `field2` = ? WHERE `field1` <> ?", 1, "x", uint64(3))
+	assertSQLWithArgs(t, db.DeleteFrom(Test).Where(Test.F2.Like("a%")),
+		"DELETE FROM `test` WHERE `f2` LIKE ?", "a%")
+
+	db.(*database).dialect = dialectPostgres
+	assertSQLWithArgs(t, db.SelectFrom(table1).Where(field1.Equals(1).Or(field2.Equals("a"))),
+		`SELECT * FROM "table1" WHERE "field1" = $1 OR "field2" = $2`, 1, "a")
+	db.(*database).dialect = dialectMSSQL
+	assertSQLWithArgs(t, db.SelectFrom(table1).Where(field1.Equals(1), field2.Equals("a")),
+		"SELECT * FROM [table1] WHERE [field1] = @p1 AND [field2] = @p2", 1, "a")
+}
+
+func TestParameterizedQuery(t *testing.T) {
+	db := newMockDatabase()
+	logger := &argsLogger{}
+	db.SetQueryLogger(logger)
+
+	_, _ = db.SelectFrom(table1).Where(field1.Equals(1)).FetchAll()
+	assertLastSql(t, "SELECT * FROM `table1` WHERE `field1` = 1")
+	if logger.args != nil {
+		t.Error(logger.args)
+	}
+
+	db.EnableParameterizedQuery(true)
+	_, err := db.SelectFrom(table1).Where(field1.Equals(1)).FetchAll()
+	if err != nil {
+		t.Error(err)
+	}
+	assertLastSql(t, "SELECT * FROM `table1` WHERE `field1` = ?")
+	if !reflect.DeepEqual(logger.args, []interface{}{1}) {
+		t.Error(logger.args)
+	}
+
+	if _, err := db.Update(table1).Set(field2, "x").Where(field1.Equals(2)).Execute(); err != nil {
+		t.Error(err)
+	}
+	assertLastSql(t, "UPDATE `table1` SET `field2` = ? WHERE `field1` = ?")
+	if !reflect.DeepEqual(logger.args, []interface{}{"x", 2}) {
+		t.Error(logger.args)
+	}
+}
+
+func TestInlineThreshold(t *testing.T) {
+	db := newMockDatabase()
+	db.SetInlineThreshold(3)
+
+	assertSQLWithArgs(t, db.SelectFrom(table1).Where(field1.In(1, 2, 3), field2.Equals("a")),
+		"SELECT * FROM `table1` WHERE `field1` IN (1, 2, 3) AND `field2` = ?", "a")
+	assertSQLWithArgs(t, db.SelectFrom(table1).Where(field1.NotIn(1, 2, 3, 4)),
+		"SELECT * FROM `table1` WHERE `field1` NOT IN (?, ?, ?, ?)", 1, 2, 3, 4)
+	assertSQLWithArgs(t, db.SelectFrom(table1).Where(field1.In(1)),
+		"SELECT * FROM `table1` WHERE `field1` = 1")
+	assertSQLWithArgs(t, db.SelectFrom(table1).Where(field1.NotIn("a")),
+		"SELECT * FROM `table1` WHERE `field1` <> 'a'")
+
+	db.SetInlineThreshold(0)
+	assertSQLWithArgs(t, db.SelectFrom(table1).Where(field1.In(1)),
+		"SELECT * FROM `table1` WHERE `field1` = ?", 1)
+}
+
+func TestPostgresArrayBinding(t *testing.T) {
+	db := newMockDatabase()
+	db.(*database).dialect = dialectPostgres
+
+	assertSQLWithArgs(t, db.SelectFrom(table1).Where(field1.In(1, 2, 3), field2.Equals("a")),
+		`SELECT * FROM "table1" WHERE "field1" = ANY($1) AND "field2" = $2`, postgresArray{1, 2, 3}, "a")
+	assertSQLWithArgs(t, db.SelectFrom(table1).Where(field2.NotIn([]string{"a", `b"\`})),
+		`SELECT * FROM "table1" WHERE "field2" <> ALL($1)`, postgresArray{"a", `b"\`})
+	assertSQLWithArgs(t, db.SelectFrom(table1).Where(field1.Add(1).In(1, nil, 2)),
+		`SELECT * FROM "table1" WHERE "field1" + $1 IS NULL OR "field1" + $2 = ANY($3)`, 1, 1, postgresArray{1, 2})
+
+	// the values that cannot be elements of an array literal are bound one by one
+	assertSQLWithArgs(t, db.SelectFrom(table1).Where(field1.In(1, field2)),
+		`SELECT * FROM "table1" WHERE "field1" IN ($1, "field2")`, 1)
+
+	for _, test := range []struct {
+		array postgresArray
+		value string
+	}{
+		{postgresArray{1, int64(-2), uint64(3)}, "{1,-2,3}"},
+		{postgresArray{1.5, true, false}, "{1.5,t,f}"},
+		{postgresArray{"a", `b"\`, ""}, `{"a","b\"\\",""}`},
+	} {
+		if value, err := test.array.Value(); err != nil || value != test.value {
+			t.Error(value, err)
+		}
+	}
+
+	// inlined on MySQL
+	db.(*database).dialect = dialectMySQL
+	assertSQLWithArgs(t, db.SelectFrom(table1).Where(field1.In(1, 2)),
+		"SELECT * FROM `table1` WHERE `field1` IN (?, ?)", 1, 2)
+}
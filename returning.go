@@ -0,0 +1,77 @@
+package sqlingo
+
+import (
+	"context"
+	"strings"
+)
+
+type toReturningWithContext interface {
+	toReturningFinal
+	WithContext(ctx context.Context) toReturningFinal
+}
+
+type toReturningFinal interface {
+	GetSQL() (string, error)
+	GetSQLWithArgs() (string, []interface{}, error)
+	FetchCursor() (Cursor, error)
+	FetchFirst(dest ...interface{}) (ok bool, err error)
+	FetchAll(dest ...interface{}) (rows int, err error)
+}
+
+// returningStatus executes an INSERT, UPDATE or DELETE statement with a RETURNING clause as a query,
+// so that the returned rows can be scanned.
+type returningStatus struct {
+	database  *database
+	statement parameterizedStatement
+	ctx       context.Context
+}
+
+func (s returningStatus) WithContext(ctx context.Context) toReturningFinal {
+	s.ctx = ctx
+	return s
+}
+
+func (s returningStatus) GetSQL() (string, error) {
+	return s.statement.GetSQL()
+}
+
+func (s returningStatus) GetSQLWithArgs() (string, []interface{}, error) {
+	return s.statement.GetSQLWithArgs()
+}
+
+func (s returningStatus) FetchCursor() (Cursor, error) {
+	return s.database.queryStatement(s.ctx, s.statement)
+}
+
+func (s returningStatus) FetchFirst(dest ...interface{}) (ok bool, err error) {
+	cursor, err := s.FetchCursor()
+	if err != nil {
+		return
+	}
+	return fetchFirst(cursor, dest...)
+}
+
+func (s returningStatus) FetchAll(dest ...interface{}) (rows int, err error) {
+	cursor, err := s.FetchCursor()
+	if err != nil {
+		return
+	}
+	return fetchAll(cursor, dest...)
+}
+
+// appendReturning appends the RETURNING clause, which is supported by PostgreSQL and SQLite.
+func appendReturning(sb *strings.Builder, scope scope, fields []Field) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	if err := checkDialect(scope, "RETURNING", dialectSqlite3, dialectPostgres); err != nil {
+		return err
+	}
+	fieldsSql, err := commaFields(scope, fields)
+	if err != nil {
+		return err
+	}
+	sb.WriteString(" RETURNING ")
+	sb.WriteString(fieldsSql)
+	return nil
+}
@@ -0,0 +1,93 @@
+package sqlingo
+
+import (
+	"context"
+	"testing"
+)
+
+func TestReturning(t *testing.T) {
+	db := newMockDatabase()
+	db.(*database).dialect = dialectPostgres
+
+	sharedMockConn.columnCount = 1
+	defer func() {
+		sharedMockConn.columnCount = 7
+	}()
+
+	var id int64
+	ok, err := db.InsertInto(Test).Fields(Test.F2).Values("a").Returning(Test.F1).FetchFirst(&id)
+	if !ok || err != nil {
+		t.Error(ok, err)
+	}
+	assertLastSql(t, `INSERT INTO "test" ("f2") VALUES ('a') RETURNING "f1"`)
+
+	var ids []int64
+	rows, err := db.Update(Test).Set(Test.F2, "b").Where(Test.F1.GreaterThan(1)).Returning(Test.F1).
+		WithContext(context.Background()).FetchAll(&ids)
+	if rows != 10 || err != nil {
+		t.Error(rows, err)
+	}
+	assertLastSql(t, `UPDATE "test" SET "f2" = 'b' WHERE "f1" > 1 RETURNING "f1"`)
+
+	sqlString, err := db.DeleteFrom(Test).Where(Test.F1.Equals(1)).Returning(Test.F1, Test.F2).GetSQL()
+	if err != nil {
+		t.Error(err)
+	}
+	assertEqual(t, sqlString, `DELETE FROM "test" WHERE "f1" = 1 RETURNING "f1", "f2"`)
+
+	db.(*database).dialect = dialectMySQL
+	if _, err := db.DeleteFrom(Test).Where(Test.F1.Equals(1)).Returning(Test.F1).GetSQL(); err == nil {
+		t.Error("should fail on MySQL")
+	}
+	db.(*database).dialect = dialectMSSQL
+	if _, err := db.InsertInto(Test).Fields(Test.F2).Values("a").Returning(Test.F1).FetchAll(&ids); err == nil {
+		t.Error("should fail on SQL Server")
+	}
+}
+
+func TestReturningModels(t *testing.T) {
+	db := newMockDatabase()
+	db.(*database).dialect = dialectPostgres
+
+	sharedMockConn.columnCount = 2
+	defer func() {
+		sharedMockConn.columnCount = 7
+	}()
+
+	var updated []TestModel
+	rows, err := db.Update(Test).Set(Test.F2, "b").Where(Test.F1.GreaterThan(1)).ReturningModels(&updated)
+	if rows != 10 || len(updated) != 10 || err != nil {
+		t.Error(rows, err)
+	}
+	assertLastSql(t, `UPDATE "test" SET "f2" = 'b' WHERE "f1" > 1 RETURNING "f1", "f2"`)
+
+	db.(*database).dialect = dialectMySQL
+	var sqls []string
+	db.SetInterceptor(func(ctx context.Context, sql string, invoker InvokerFunc) error {
+		sqls = append(sqls, sql)
+		return invoker(ctx, sql)
+	})
+	sharedMockConn.columnCount = 1
+	sharedMockConn.rowCount = 2
+	defer func() {
+		sharedMockConn.rowCount = 10
+	}()
+	var keys []struct{ F1 int64 }
+	rows, err = db.Update(Test).Set(Test.F2, "b").Where(Test.F1.GreaterThan(1)).Limit(2).ReturningModels(&keys)
+	if rows != 2 || err != nil {
+		t.Error(rows, err)
+	}
+	if len(sqls) != 3 {
+		t.Fatal(sqls)
+	}
+	assertEqual(t, sqls[0], "SELECT `f1` FROM `test` WHERE `f1` > 1 LIMIT 2 FOR UPDATE")
+	assertEqual(t, sqls[1], "UPDATE `test` SET `f2` = 'b' WHERE `f1` IN (1, 2)")
+	assertEqual(t, sqls[2], "SELECT * FROM `test` WHERE `f1` IN (1, 2)")
+
+	if _, err := db.Update(Test).Set(Test.F1, 1).Where(Test.F2.Equals("a")).ReturningModels(&keys); err == nil {
+		t.Error("should fail on setting the primary key")
+	}
+	if _, err := db.Update(Table1).Set(field2, 1).Where(field1.Equals(1)).ReturningModels(&keys); err == nil {
+		t.Error("should fail without a primary key")
+	}
+}
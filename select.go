@@ -3,6 +3,7 @@ package sqlingo
 import (
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
 	"strconv"
 	"strings"
@@ -21,6 +22,7 @@ type selectWithTables interface {
 	toSelectWithContext
 	toSelectFinal
 	toUnionSelect
+	toSetOperation
 	GroupBy(expressions ...Expression) selectWithGroupBy
 	OrderBy(orderBys ...OrderBy) selectWithOrder
 	Limit(limit int) selectWithLimit
@@ -30,7 +32,11 @@ type toSelectJoin interface {
 	Join(table Table) selectWithJoin
 	LeftJoin(table Table) selectWithJoin
 	RightJoin(table Table) selectWithJoin
+	FullJoin(table Table) selectWithJoin
+	CrossJoin(table Table) selectWithJoinOn
 	NaturalJoin(table Table) selectWithJoinOn
+	NaturalLeftJoin(table Table) selectWithJoinOn
+	NaturalRightJoin(table Table) selectWithJoinOn
 }
 
 type selectWithJoin interface {
@@ -43,6 +49,7 @@ type selectWithJoinOn interface {
 	toSelectWithContext
 	toSelectFinal
 	toUnionSelect
+	toSetOperation
 	toSelectJoin
 	GroupBy(expressions ...Expression) selectWithGroupBy
 	OrderBy(orderBys ...OrderBy) selectWithOrder
@@ -52,6 +59,7 @@ type selectWithJoinOn interface {
 type toSelectWhere interface {
 	Where(conditions ...BooleanExpression) selectWithWhere
 	WhereIf(prerequisite bool, conditions ...BooleanExpression) selectWithWhere
+	WhereRaw(sql string, args ...interface{}) selectWithWhere
 }
 
 type selectWithWhere interface {
@@ -60,6 +68,7 @@ type selectWithWhere interface {
 	toSelectWithContext
 	toSelectFinal
 	toUnionSelect
+	toSetOperation
 	GroupBy(expressions ...Expression) selectWithGroupBy
 	OrderBy(orderBys ...OrderBy) selectWithOrder
 	Limit(limit int) selectWithLimit
@@ -70,6 +79,7 @@ type selectWithGroupBy interface {
 	toSelectWithContext
 	toSelectFinal
 	toUnionSelect
+	toSetOperation
 	Having(conditions ...BooleanExpression) selectWithGroupByHaving
 	OrderBy(orderBys ...OrderBy) selectWithOrder
 	Limit(limit int) selectWithLimit
@@ -80,6 +90,7 @@ type selectWithGroupByHaving interface {
 	toSelectWithContext
 	toSelectFinal
 	toUnionSelect
+	toSetOperation
 	OrderBy(orderBys ...OrderBy) selectWithOrder
 }
 
@@ -88,6 +99,12 @@ type selectWithOrder interface {
 	toSelectWithContext
 	toSelectFinal
 	Limit(limit int) selectWithLimit
+	LimitWithTies(limit int) selectWithLimitWithTies
+}
+
+type selectWithLimitWithTies interface {
+	toSelectWithContext
+	toSelectFinal
 }
 
 type selectWithLimit interface {
@@ -108,6 +125,7 @@ type toSelectWithLock interface {
 	ForUpdate() selectWithLock
 	ForUpdateNoWait() selectWithLock
 	ForUpdateSkipLocked() selectWithLock
+	ForUpdateOf(tables ...Table) selectWithLock
 }
 
 type selectWithLock interface {
@@ -128,15 +146,36 @@ type toUnionSelect interface {
 	UnionAllSelectDistinct(fields ...interface{}) selectWithFields
 }
 
+type toSetOperation interface {
+	Union(other toSelectFinal) selectWithSetOperation
+	UnionAll(other toSelectFinal) selectWithSetOperation
+	Intersect(other toSelectFinal) selectWithSetOperation
+	Except(other toSelectFinal) selectWithSetOperation
+}
+
+type selectWithSetOperation interface {
+	toSetOperation
+	toSelectWithContext
+	toSelectFinal
+	OrderBy(orderBys ...OrderBy) selectWithOrder
+	Limit(limit int) selectWithLimit
+}
+
 type toSelectFinal interface {
+	With(ctes ...CTE) toSelectFinal
+	RouteHint(hint string) toSelectFinal
 	Exists() (bool, error)
 	Count() (int, error)
 	GetSQL() (string, error)
 	FetchFirst(out ...interface{}) (bool, error)
 	FetchExactlyOne(out ...interface{}) error
 	FetchAll(dest ...interface{}) (rows int, err error)
+	FetchInBatches(batchSize int, callback interface{}) error
 	FetchCursor() (Cursor, error)
 	FetchSeq() func(yield func(row Scanner) bool) // use with "range over function" in Go 1.22
+	Fingerprint() (string, error)
+	GetSQLWithArgs() (string, []interface{}, error)
+	As(alias string) DerivedTable
 }
 
 type join struct {
@@ -149,6 +188,7 @@ type join struct {
 type selectBase struct {
 	scope    scope
 	distinct bool
+	top      string
 	fields   fieldList
 	where    BooleanExpression
 	groupBys []Expression
@@ -156,13 +196,19 @@ type selectBase struct {
 }
 
 type selectStatus struct {
+	with      *withClause
 	base      selectBase
 	orderBys  []OrderBy
 	lastUnion *unionSelectStatus
 	limit     *int
+	withTies  bool
 	offset    int
 	ctx       context.Context
 	lock      string
+	lockOf    []Table
+	routeHint string
+	// aliasedColumns names the output columns c1, c2, ..., cN, as a member of an emulated EXCEPT
+	aliasedColumns bool
 }
 
 type errorScanner struct {
@@ -184,15 +230,19 @@ func (s selectStatus) FetchSeq() func(yield func(row Scanner) bool) {
 		defer cursor.Close()
 		for cursor.Next() {
 			if !yield(cursor) {
-				break
+				return
 			}
 		}
+		if bounded, ok := cursor.(*boundedCursor); ok && bounded.err != nil {
+			yield(errorScanner{bounded.err})
+		}
 	}
 }
 
 type unionSelectStatus struct {
 	base     selectBase
-	all      bool
+	operator string
+	query    toSelectFinal // set only when a whole query is combined, e.g. by Except
 	previous *unionSelectStatus
 }
 
@@ -215,19 +265,33 @@ func (s selectStatus) RightJoin(table Table) selectWithJoin {
 	return s.join("RIGHT ", table)
 }
 
+// FullJoin joins the table using FULL JOIN, which is not supported by MySQL.
+func (s selectStatus) FullJoin(table Table) selectWithJoin {
+	return s.join("FULL ", table)
+}
+
+// CrossJoin joins the table using CROSS JOIN, which takes no ON condition.
+func (s selectStatus) CrossJoin(table Table) selectWithJoinOn {
+	return s.join("CROSS ", table).(selectStatus)
+}
+
 // NaturalJoin joins the table using the NATURAL keyword.
 // it automatically matches the columns in the two tables that have the same name.
 // it not be needed but be provided for completeness.
+// Beware that the join condition silently changes when a column is added to either table with a name
+// that the other table also has, e.g. created_at. It is not supported by SQL Server.
 func (s selectStatus) NaturalJoin(table Table) selectWithJoinOn {
-	base := activeSelectBase(&s)
-	base.scope.lastJoin = &join{
-		previous: base.scope.lastJoin,
-		prefix:   "NATURAL ",
-		table:    table,
-	}
-	join := *base.scope.lastJoin
-	base.scope.lastJoin = &join
-	return s
+	return s.join("NATURAL ", table).(selectStatus)
+}
+
+// NaturalLeftJoin is like NaturalJoin, but using NATURAL LEFT JOIN.
+func (s selectStatus) NaturalLeftJoin(table Table) selectWithJoinOn {
+	return s.join("NATURAL LEFT ", table).(selectStatus)
+}
+
+// NaturalRightJoin is like NaturalJoin, but using NATURAL RIGHT JOIN.
+func (s selectStatus) NaturalRightJoin(table Table) selectWithJoinOn {
+	return s.join("NATURAL RIGHT ", table).(selectStatus)
 }
 
 func (s selectStatus) join(prefix string, table Table) selectWithJoin {
@@ -320,6 +384,11 @@ func (s selectStatus) Where(conditions ...BooleanExpression) selectWithWhere {
 	return s
 }
 
+// WhereRaw adds a condition in raw SQL, where the ? placeholders are replaced by the quoted args.
+func (s selectStatus) WhereRaw(sql string, args ...interface{}) selectWithWhere {
+	return s.Where(Raw(sql, args...))
+}
+
 func (s selectStatus) WhereIf(prerequisite bool, conditions ...BooleanExpression) selectWithWhere {
 	if !prerequisite {
 		return s
@@ -367,6 +436,10 @@ func (s selectStatus) UnionAllSelectDistinct(fields ...interface{}) selectWithFi
 }
 
 func (s selectStatus) withUnionSelect(all bool, distinct bool, fields []interface{}, tables []Table) selectStatus {
+	operator := "UNION"
+	if all {
+		operator = "UNION ALL"
+	}
 	s.lastUnion = &unionSelectStatus{
 		base: selectBase{
 			scope: scope{
@@ -376,12 +449,176 @@ func (s selectStatus) withUnionSelect(all bool, distinct bool, fields []interfac
 			distinct: distinct,
 			fields:   getFields(fields),
 		},
-		all:      all,
+		operator: operator,
+		previous: s.lastUnion,
+	}
+	return s
+}
+
+// Union combines the select with another query using the UNION operator.
+func (s selectStatus) Union(other toSelectFinal) selectWithSetOperation {
+	return s.withSetOperation("UNION", other)
+}
+
+// UnionAll combines the select with another query using the UNION ALL operator.
+func (s selectStatus) UnionAll(other toSelectFinal) selectWithSetOperation {
+	return s.withSetOperation("UNION ALL", other)
+}
+
+// Intersect combines the select with another query using the INTERSECT operator, which is not supported by MySQL.
+func (s selectStatus) Intersect(other toSelectFinal) selectWithSetOperation {
+	return s.withSetOperation("INTERSECT", other)
+}
+
+// Except combines the select with another query using the EXCEPT operator.
+// On MySQL, which lacks EXCEPT before 8.0.31, it is emulated with an anti-join over all the projected columns.
+func (s selectStatus) Except(other toSelectFinal) selectWithSetOperation {
+	return s.withSetOperation("EXCEPT", other)
+}
+
+func (s selectStatus) withSetOperation(operator string, other toSelectFinal) selectStatus {
+	s.lastUnion = &unionSelectStatus{
+		operator: operator,
+		query:    other,
 		previous: s.lastUnion,
 	}
 	return s
 }
 
+func (s selectStatus) hasSetOperation() bool {
+	for union := s.lastUnion; union != nil; union = union.previous {
+		if union.query != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (s selectStatus) hasEmulatedExcept() bool {
+	if getDialect(s.base.scope) != dialectMySQL {
+		return false
+	}
+	for union := s.lastUnion; union != nil; union = union.previous {
+		if union.operator == "EXCEPT" {
+			return true
+		}
+	}
+	return false
+}
+
+func (s selectStatus) projectedFields() ([]Field, error) {
+	if len(s.base.fields) > 0 {
+		return s.base.fields, nil
+	}
+	var fields []Field
+	for _, table := range s.base.scope.Tables {
+		fields = append(fields, table.GetFields()...)
+	}
+	if len(fields) == 0 {
+		return nil, errors.New("cannot determine the projected columns")
+	}
+	return fields, nil
+}
+
+// withColumnAliases renames the output columns of the select to c1, c2, ..., cN.
+func (s selectStatus) withColumnAliases() (selectStatus, int, error) {
+	fields, err := s.projectedFields()
+	if err != nil {
+		return s, 0, err
+	}
+	if s.hasEmulatedExcept() {
+		// the emulation names its output columns by itself
+		s.aliasedColumns = true
+		return s, len(fields), nil
+	}
+	aliasedFields := make([]Field, len(fields))
+	for i, field := range fields {
+		aliasedFields[i] = field.As("c" + strconv.Itoa(i+1)).(aliasExpression)
+	}
+	s.base.fields = aliasedFields
+	return s, len(fields), nil
+}
+
+// buildExceptByAntiJoin emulates "left EXCEPT right" as
+// SELECT DISTINCT l.c1 AS name1, ... FROM (left) AS l LEFT JOIN (SELECT *, 1 AS __m FROM (right) AS r0) AS r
+// ON l.c1 <=> r.c1 AND ... WHERE r.__m IS NULL,
+// where the marker tells the missing rows apart from the matched rows with NULLs. The output columns are named
+// after the fields of left so that they can be ordered by, unless aliasedColumns keeps them c1, c2, ..., cN.
+func buildExceptByAntiJoin(left selectStatus, right toSelectFinal, aliasedColumns bool) (string, error) {
+	rightStatus, ok := right.(selectStatus)
+	if !ok {
+		return "", errors.New("EXCEPT emulation requires a select statement")
+	}
+	fields, err := left.projectedFields()
+	if err != nil {
+		return "", err
+	}
+	left, leftColumnCount, err := left.withColumnAliases()
+	if err != nil {
+		return "", err
+	}
+	rightStatus, rightColumnCount, err := rightStatus.withColumnAliases()
+	if err != nil {
+		return "", err
+	}
+	if leftColumnCount != rightColumnCount {
+		return "", errors.New("EXCEPT requires both queries to have the same number of columns")
+	}
+	args := left.base.scope.args
+	leftSql, err := left.buildSQL(args)
+	if err != nil {
+		return "", err
+	}
+	rightSql, err := rightStatus.buildSQL(args)
+	if err != nil {
+		return "", err
+	}
+
+	dialect := getDialect(left.base.scope)
+	l := quoteIdentifier("l")[dialect]
+	r := quoteIdentifier("r")[dialect]
+	var sb strings.Builder
+	sb.Grow(len(leftSql) + len(rightSql) + 128)
+	sb.WriteString("SELECT DISTINCT ")
+	for i := 1; i <= leftColumnCount; i++ {
+		if i > 1 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(l + "." + quoteIdentifier("c" + strconv.Itoa(i))[dialect])
+		if name := getOutputColumnName(fields[i-1]); !aliasedColumns && name != "" {
+			sb.WriteString(" AS " + quoteIdentifier(name)[dialect])
+		}
+	}
+	marker := quoteIdentifier("__m")[dialect]
+	sb.WriteString(" FROM (" + leftSql + ") AS " + l)
+	sb.WriteString(" LEFT JOIN (SELECT *, 1 AS " + marker + " FROM (" + rightSql + ") AS " + quoteIdentifier("r0")[dialect] +
+		") AS " + r + " ON ")
+	for i := 1; i <= leftColumnCount; i++ {
+		if i > 1 {
+			sb.WriteString(" AND ")
+		}
+		column := quoteIdentifier("c" + strconv.Itoa(i))[dialect]
+		sb.WriteString(l + "." + column + " <=> " + r + "." + column)
+	}
+	sb.WriteString(" WHERE " + r + "." + marker + " IS NULL")
+	return sb.String(), nil
+}
+
+// getOutputColumnName returns the name of the column of the field in the result, or empty for an expression.
+func getOutputColumnName(field Field) string {
+	if alias, ok := field.(aliasExpression); ok {
+		return alias.alias
+	}
+	if field.GetTable() == nil {
+		return ""
+	}
+	name, err := getFieldName(field)
+	if err != nil {
+		return ""
+	}
+	return name
+}
+
 func (s selectStatus) OrderBy(orderBys ...OrderBy) selectWithOrder {
 	s.orderBys = orderBys
 	return s
@@ -392,6 +629,14 @@ func (s selectStatus) Limit(limit int) selectWithLimit {
 	return s
 }
 
+// LimitWithTies limits the number of rows, including the extra rows that tie with the last one on the ORDER BY.
+// It is supported by PostgreSQL and SQL Server.
+func (s selectStatus) LimitWithTies(limit int) selectWithLimitWithTies {
+	s.limit = &limit
+	s.withTies = true
+	return s
+}
+
 func (s selectStatus) Offset(offset int) selectWithOffset {
 	s.offset = offset
 	return s
@@ -415,11 +660,15 @@ func (s selectStatus) Count() (count int, err error) {
 			_, err = s.FetchFirst(&count)
 		}
 	} else {
-		if !s.base.distinct {
+		if !s.base.distinct && !s.hasSetOperation() {
 			s.base.fields = []Field{staticExpression("1", 0, false)}
 		}
+		// the hint is moved to the wrapping select, as the proxy reads it from the head of the statement
+		routeHint := s.routeHint
+		s.routeHint = ""
 		_, err = s.base.scope.Database.Select(Function("COUNT", 1)).
 			From(s.asDerivedTable("t")).
+			RouteHint(routeHint).
 			FetchFirst(&count)
 	}
 
@@ -446,6 +695,24 @@ func (s selectStatus) ForUpdateSkipLocked() selectWithLock {
 	return s
 }
 
+// ForUpdateOf locks only the rows of the given tables in a join, e.g. FOR UPDATE OF t1, t2.
+// It is supported by PostgreSQL and MySQL 8.
+func (s selectStatus) ForUpdateOf(tables ...Table) selectWithLock {
+	s.lock = " FOR UPDATE"
+	s.lockOf = tables
+	return s
+}
+
+// As makes the select a derived table with the alias, i.e. (SELECT ...) AS alias, to select from or join.
+// Its columns are referenced by Column or the fields of GetFields. The alias is required and must be
+// a plain identifier.
+func (s selectStatus) As(alias string) DerivedTable {
+	return aliasedTable{derivedTable{
+		name:         alias,
+		selectStatus: s,
+	}}
+}
+
 func (s selectStatus) asDerivedTable(name string) Table {
 	return derivedTable{
 		name:         name,
@@ -454,15 +721,31 @@ func (s selectStatus) asDerivedTable(name string) Table {
 }
 
 func (s selectStatus) Exists() (exists bool, err error) {
-	_, err = s.base.scope.Database.Select(command("EXISTS", s)).FetchFirst(&exists)
+	routeHint := s.routeHint
+	s.routeHint = ""
+	var value interface{} = command("EXISTS", s)
+	if getDialect(s.base.scope) == dialectMSSQL {
+		// SQL Server does not allow a predicate in the select list
+		value = Case().WhenThen(Exists(s), 1).Else(0)
+	}
+	_, err = s.base.scope.Database.Select(value).RouteHint(routeHint).FetchFirst(&exists)
 	return
 }
 
+// getFields returns the select list, with the duplicate column names aliased if it is enabled.
+func (s selectBase) getFields() fieldList {
+	if s.scope.Database != nil && s.scope.Database.duplicateColumnAliases {
+		return s.fields.aliasDuplicates()
+	}
+	return s.fields
+}
+
 func (s selectBase) buildSelectBase(sb *strings.Builder) error {
 	sb.WriteString("SELECT ")
 	if s.distinct {
 		sb.WriteString("DISTINCT ")
 	}
+	sb.WriteString(s.top)
 
 	// find tables from fields if "From" is not specified
 	if len(s.scope.Tables) == 0 && len(s.fields) > 0 {
@@ -485,18 +768,27 @@ func (s selectBase) buildSelectBase(sb *strings.Builder) error {
 		}
 	}
 
-	fieldsSql, err := s.fields.GetSQL(s.scope)
+	fieldsSql, err := s.getFields().GetSQL(s.scope)
 	if err != nil {
 		return err
 	}
 	sb.WriteString(fieldsSql)
 
 	if len(s.scope.Tables) > 0 {
-		fromSql := commaTables(s.scope, s.scope.Tables)
+		fromSql, err := commaTables(s.scope, s.scope.Tables)
+		if err != nil {
+			return err
+		}
 		sb.WriteString(" FROM ")
 		sb.WriteString(fromSql)
 	}
 
+	if s.scope.Database != nil && s.scope.Database.cartesianProductCheck {
+		if err := s.checkCartesianProduct(); err != nil {
+			return err
+		}
+	}
+
 	if s.scope.lastJoin != nil {
 		var joins []*join
 		for j := s.scope.lastJoin; j != nil; j = j.previous {
@@ -504,10 +796,24 @@ func (s selectBase) buildSelectBase(sb *strings.Builder) error {
 		}
 		for i := len(joins) - 1; i >= 0; i-- {
 			join := joins[i]
+			if join.prefix == "FULL " {
+				if err := checkDialect(s.scope, "FULL JOIN", dialectSqlite3, dialectPostgres, dialectMSSQL); err != nil {
+					return err
+				}
+			}
+			if strings.HasPrefix(join.prefix, "NATURAL ") {
+				if err := checkDialect(s.scope, "NATURAL JOIN", dialectMySQL, dialectSqlite3, dialectPostgres); err != nil {
+					return err
+				}
+			}
 			sb.WriteString(" ")
 			sb.WriteString(join.prefix)
 			sb.WriteString("JOIN ")
-			sb.WriteString(join.table.GetSQL(s.scope))
+			tableSql, err := getTableSQL(s.scope, join.table)
+			if err != nil {
+				return err
+			}
+			sb.WriteString(tableSql)
 			// cause on isn't a required part of join when using natural join,
 			// so move it to if statement
 			if join.on != nil {
@@ -533,7 +839,7 @@ func (s selectBase) buildSelectBase(sb *strings.Builder) error {
 		sb.WriteString(" GROUP BY ")
 		sb.WriteString(groupBySql)
 
-		if s.having != nil {
+		if e, ok := s.having.(expression); s.having != nil && !(ok && e.isTrue) {
 			havingSql, err := s.having.GetSQL(s.scope)
 			if err != nil {
 				return err
@@ -546,26 +852,156 @@ func (s selectBase) buildSelectBase(sb *strings.Builder) error {
 	return nil
 }
 
+// checkCartesianProduct reports an error if there are several tables in FROM and
+// any of them is not mentioned in the WHERE clause.
+func (s selectBase) checkCartesianProduct() error {
+	if len(s.scope.Tables) < 2 {
+		return nil
+	}
+	whereSql := ""
+	if s.where != nil {
+		// rendered only for inspection, so the arguments are not collected
+		whereScope := s.scope
+		whereScope.args = nil
+		var err error
+		if whereSql, err = s.where.GetSQL(whereScope); err != nil {
+			return err
+		}
+	}
+	dialect := getDialect(s.scope)
+	for _, table := range s.scope.Tables {
+		if !containsQualifier(whereSql, getTableSQLArray(table)[dialect]) {
+			return fmt.Errorf("table %s is not linked to the other tables, which results in a Cartesian product", table.GetName())
+		}
+	}
+	return nil
+}
+
+// containsQualifier reports whether a column is qualified by the quoted table name in the SQL, as the fields
+// of the table render it. The quotes keep `t1`.`c` from matching `t10`.`c`, and a qualifier preceded by a dot
+// is of a table of another database.
+func containsQualifier(sql string, qualifier string) bool {
+	qualifier += "."
+	for start := 0; ; {
+		i := strings.Index(sql[start:], qualifier)
+		if i < 0 {
+			return false
+		}
+		i += start
+		if i == 0 || sql[i-1] != '.' {
+			return true
+		}
+		start = i + 1
+	}
+}
+
+// RouteHint prepends the hint as a comment to the statement for a sharding proxy, e.g. "shard=users_3"
+// for /* shard=users_3 */. Unlike the caller info, it is part of the SQL returned by GetSQL.
+func (s selectStatus) RouteHint(hint string) toSelectFinal {
+	s.routeHint = hint
+	return s
+}
+
 func (s selectStatus) GetSQL() (string, error) {
+	return s.base.scope.Database.finishSQL(s.buildSQL(nil))
+}
+
+// GetSQLWithArgs returns the SQL with placeholders and the values bound to them.
+func (s selectStatus) GetSQLWithArgs() (string, []interface{}, error) {
+	args := &argList{}
+	sqlString, err := s.base.scope.Database.finishSQL(s.buildSQL(args))
+	return sqlString, args.values, err
+}
+
+func (s selectStatus) buildSQL(args *argList) (string, error) {
+	s.base.scope.args = args
 	var sb strings.Builder
 	sb.Grow(128)
-
-	if err := s.base.buildSelectBase(&sb); err != nil {
+	if err := appendRouteHint(&sb, s.routeHint); err != nil {
 		return "", err
 	}
 
+	if s.withTies {
+		if err := checkDialect(s.base.scope, "LIMIT WITH TIES", dialectPostgres, dialectMSSQL); err != nil {
+			return "", err
+		}
+		if getDialect(s.base.scope) == dialectMSSQL {
+			if s.lastUnion != nil {
+				return "", errors.New("LIMIT WITH TIES cannot be combined with UNION on SQL Server")
+			}
+			s.base.top = "TOP (" + strconv.Itoa(*s.limit) + ") WITH TIES "
+		}
+	} else if s.limit != nil && s.offset == 0 && len(s.orderBys) == 0 && s.lastUnion == nil &&
+		getDialect(s.base.scope) == dialectMSSQL {
+		// OFFSET ... FETCH requires ORDER BY, so a plain limit is rendered as TOP
+		s.base.top = "TOP (" + strconv.Itoa(*s.limit) + ") "
+		s.limit = nil
+	}
+
+	if s.with != nil {
+		if err := s.with.buildWith(&sb, getDialect(s.base.scope), args); err != nil {
+			return "", err
+		}
+	}
+
 	var unions []*unionSelectStatus
 	for union := s.lastUnion; union != nil; union = union.previous {
 		unions = append(unions, union)
 	}
-	for i := len(unions) - 1; i >= 0; i-- {
+
+	first := len(unions) - 1
+	if s.hasEmulatedExcept() {
+		// everything up to the last EXCEPT is replaced by the emulation
+		for i, union := range unions {
+			if union.operator != "EXCEPT" {
+				continue
+			}
+			left := s
+			left.with = nil
+			left.lastUnion = union.previous
+			left.orderBys = nil
+			left.limit = nil
+			left.offset = 0
+			left.lock = ""
+			left.lockOf = nil
+			exceptSql, err := buildExceptByAntiJoin(left, union.query, s.aliasedColumns)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(exceptSql)
+			first = i - 1
+			break
+		}
+	} else if err := s.base.buildSelectBase(&sb); err != nil {
+		return "", err
+	}
+
+	for i := first; i >= 0; i-- {
 		union := unions[i]
-		if union.all {
-			sb.WriteString(" UNION ALL ")
-		} else {
-			sb.WriteString(" UNION ")
+		sb.WriteString(" ")
+		sb.WriteString(union.operator)
+		sb.WriteString(" ")
+		if union.query != nil {
+			if union.operator == "INTERSECT" {
+				if err := checkDialect(s.base.scope, "INTERSECT", dialectSqlite3, dialectPostgres, dialectMSSQL); err != nil {
+					return "", err
+				}
+			}
+			querySql, err := getStatementSQL(args, union.query)
+			if err != nil {
+				return "", err
+			}
+			if getDialect(s.base.scope) == dialectSqlite3 {
+				// SQLite does not allow parenthesized compound members
+				sb.WriteString(querySql)
+			} else {
+				sb.WriteString("(" + querySql + ")")
+			}
+			continue
 		}
-		if err := union.base.buildSelectBase(&sb); err != nil {
+		base := union.base
+		base.scope.args = args
+		if err := base.buildSelectBase(&sb); err != nil {
 			return "", err
 		}
 	}
@@ -579,44 +1015,134 @@ func (s selectStatus) GetSQL() (string, error) {
 		sb.WriteString(orderBySql)
 	}
 
-	if s.limit != nil {
-		sb.WriteString(" LIMIT ")
-		sb.WriteString(strconv.Itoa(*s.limit))
-	}
+	if s.withTies {
+		if s.base.top == "" {
+			sb.WriteString(" FETCH FIRST ")
+			sb.WriteString(strconv.Itoa(*s.limit))
+			sb.WriteString(" ROWS WITH TIES")
+		}
+	} else if getDialect(s.base.scope) == dialectMSSQL {
+		if err := s.appendOffsetFetch(&sb); err != nil {
+			return "", err
+		}
+	} else {
+		if s.limit != nil {
+			sb.WriteString(" LIMIT ")
+			sb.WriteString(strconv.Itoa(*s.limit))
+		}
 
-	if s.offset != 0 {
-		sb.WriteString(" OFFSET ")
-		sb.WriteString(strconv.Itoa(s.offset))
+		if s.offset != 0 {
+			sb.WriteString(" OFFSET ")
+			sb.WriteString(strconv.Itoa(s.offset))
+		}
 	}
 
 	sb.WriteString(s.lock)
+	if len(s.lockOf) > 0 {
+		if err := checkDialect(s.base.scope, "FOR UPDATE OF", dialectMySQL, dialectPostgres); err != nil {
+			return "", err
+		}
+		dialect := getDialect(s.base.scope)
+		sb.WriteString(" OF ")
+		for i, table := range s.lockOf {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(getTableSQLArray(table)[dialect])
+		}
+	}
 
 	return sb.String(), nil
 }
 
+// appendOffsetFetch renders the limit and offset in the OFFSET ... FETCH NEXT syntax of SQL Server,
+// which is only allowed after ORDER BY.
+func (s selectStatus) appendOffsetFetch(sb *strings.Builder) error {
+	if s.limit == nil && s.offset == 0 {
+		return nil
+	}
+	if len(s.orderBys) == 0 {
+		return errors.New("LIMIT and OFFSET require ORDER BY on SQL Server")
+	}
+	sb.WriteString(" OFFSET ")
+	sb.WriteString(strconv.Itoa(s.offset))
+	sb.WriteString(" ROWS")
+	if s.limit != nil {
+		sb.WriteString(" FETCH NEXT ")
+		sb.WriteString(strconv.Itoa(*s.limit))
+		sb.WriteString(" ROWS ONLY")
+	}
+	return nil
+}
+
 func (s selectStatus) WithContext(ctx context.Context) toSelectFinal {
 	s.ctx = ctx
 	return s
 }
 
+// FetchCursor queries the rows. If SetMaxFetchRows is set and the select has no limit, LIMIT n+1 is added,
+// and the cursor stops with an error after n rows, which is returned by Close.
 func (s selectStatus) FetchCursor() (Cursor, error) {
-	sqlString, err := s.GetSQL()
+	d := s.base.scope.Database
+	if d == nil || d.maxFetchRows <= 0 || s.limit != nil {
+		return s.queryCursor()
+	}
+	// one more row than allowed tells a select reaching the limit from one exceeding it
+	limit := d.maxFetchRows + 1
+	s.limit = &limit
+	cursor, err := s.queryCursor()
 	if err != nil {
 		return nil, err
 	}
+	return &boundedCursor{Cursor: cursor, maxRows: d.maxFetchRows}, nil
+}
 
-	cursor, err := s.base.scope.Database.QueryContext(s.ctx, sqlString)
+func (s selectStatus) queryCursor() (Cursor, error) {
+	cursor, err := s.base.scope.Database.queryStatement(s.ctx, s)
 	if err != nil {
 		return nil, err
 	}
-	return cursor, nil
+	return withNullableColumns(cursor, s.base.fields), nil
 }
 
+// boundedCursor fails a select without a limit that returns more rows than set by SetMaxFetchRows.
+type boundedCursor struct {
+	Cursor
+	maxRows int
+	rows    int
+	err     error
+}
+
+func (c *boundedCursor) Next() bool {
+	if c.err != nil || !c.Cursor.Next() {
+		return false
+	}
+	c.rows++
+	if c.rows > c.maxRows {
+		c.err = fmt.Errorf("the select without a limit returned more than %d rows, the limit set by SetMaxFetchRows", c.maxRows)
+		return false
+	}
+	return true
+}
+
+func (c *boundedCursor) Close() error {
+	err := c.Cursor.Close()
+	if c.err != nil {
+		return c.err
+	}
+	return err
+}
+
+// FetchFirst reads a single row and FetchExactlyOne at most two, so neither is bounded by SetMaxFetchRows.
 func (s selectStatus) FetchFirst(dest ...interface{}) (ok bool, err error) {
-	cursor, err := s.FetchCursor()
+	cursor, err := s.queryCursor()
 	if err != nil {
 		return
 	}
+	return fetchFirst(cursor, dest...)
+}
+
+func fetchFirst(cursor Cursor, dest ...interface{}) (ok bool, err error) {
 	defer cursor.Close()
 
 	for cursor.Next() {
@@ -632,7 +1158,7 @@ func (s selectStatus) FetchFirst(dest ...interface{}) (ok bool, err error) {
 }
 
 func (s selectStatus) FetchExactlyOne(dest ...interface{}) (err error) {
-	cursor, err := s.FetchCursor()
+	cursor, err := s.queryCursor()
 	if err != nil {
 		return
 	}
@@ -655,7 +1181,7 @@ func (s selectStatus) FetchExactlyOne(dest ...interface{}) (err error) {
 	return
 }
 
-func (s selectStatus) fetchAllAsMap(cursor Cursor, mapType reflect.Type) (mapValue reflect.Value, err error) {
+func fetchAllAsMap(cursor Cursor, mapType reflect.Type) (mapValue reflect.Value, err error) {
 	mapValue = reflect.MakeMap(mapType)
 	key := reflect.New(mapType.Key())
 	elem := reflect.New(mapType.Elem())
@@ -676,6 +1202,14 @@ func (s selectStatus) FetchAll(dest ...interface{}) (rows int, err error) {
 	if err != nil {
 		return
 	}
+	rows, err = fetchAll(cursor, dest...)
+	if bounded, ok := cursor.(*boundedCursor); ok && err == nil && bounded.err != nil {
+		err = bounded.err
+	}
+	return
+}
+
+func fetchAll(cursor Cursor, dest ...interface{}) (rows int, err error) {
 	defer cursor.Close()
 
 	count := len(dest)
@@ -696,7 +1230,7 @@ func (s selectStatus) FetchAll(dest ...interface{}) (rows int, err error) {
 				return
 			}
 			var mapValue reflect.Value
-			mapValue, err = s.fetchAllAsMap(cursor, val.Type())
+			mapValue, err = fetchAllAsMap(cursor, val.Type())
 			if err != nil {
 				return
 			}
@@ -726,3 +1260,145 @@ func (s selectStatus) FetchAll(dest ...interface{}) (rows int, err error) {
 	}
 	return
 }
+
+// scannedValue keeps a column as it is returned by the driver, e.g. a key to be sent back in a later query.
+// The text of a number or a boolean, as MySQL returns in the text protocol, is converted back by the kind of
+// the field, so that it is sent as it was rather than as a string.
+type scannedValue struct {
+	kind  columnKind
+	value interface{}
+}
+
+// newScannedValue creates a scannedValue for a column of the field.
+func newScannedValue(field Field) *scannedValue {
+	v := &scannedValue{}
+	if column := field.getColumn(); column != nil {
+		v.kind = column.kind
+	}
+	return v
+}
+
+func (v *scannedValue) Scan(src interface{}) error {
+	if b, ok := src.([]byte); ok {
+		// the driver reuses the buffer
+		if v.kind == columnBinary {
+			src = append([]byte{}, b...)
+		} else {
+			src = string(b)
+		}
+	}
+	if s, ok := src.(string); ok {
+		switch v.kind {
+		case columnNumber:
+			if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+				src = i
+			} else if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+				src = u
+			} else if f, err := strconv.ParseFloat(s, 64); err == nil {
+				src = f
+			}
+		case columnBoolean:
+			if b, err := strconv.ParseBool(s); err == nil {
+				src = b
+			}
+		}
+	}
+	v.value = src
+	return nil
+}
+
+// FetchInBatches fetches the rows batchSize at a time, one query per batch, and calls callback with each batch.
+// callback should be a func([]T) error, where T is anything that a row can be scanned into. It stops at the first
+// error returned by callback.
+// If the statement selects from a single table with a single-column primary key and has no ORDER BY, GROUP BY,
+// DISTINCT or set operations, the batches are paginated by the key (WHERE key > last key ORDER BY key LIMIT n)
+// to avoid the cost of deep offsets. Otherwise they are paginated by LIMIT and OFFSET.
+func (s selectStatus) FetchInBatches(batchSize int, callback interface{}) error {
+	if batchSize <= 0 {
+		return errors.New("batch size should be positive")
+	}
+	callbackValue := reflect.ValueOf(callback)
+	callbackType := callbackValue.Type()
+	if callbackType.Kind() != reflect.Func ||
+		callbackType.NumIn() != 1 || callbackType.In(0).Kind() != reflect.Slice ||
+		callbackType.NumOut() != 1 || callbackType.Out(0) != reflect.TypeOf((*error)(nil)).Elem() {
+		return errors.New("callback should be a func([]T) error")
+	}
+	if s.limit != nil || s.offset != 0 {
+		return errors.New("FetchInBatches cannot be combined with LIMIT or OFFSET")
+	}
+	batchType := callbackType.In(0)
+
+	keyField := s.getKeysetField()
+	var lastKey *scannedValue
+	for offset := 0; ; offset += batchSize {
+		page := s
+		page.limit = &batchSize
+		if keyField != nil {
+			fields, err := s.projectedFields()
+			if err != nil {
+				return err
+			}
+			page.base.fields = append(append(fieldList{}, fields...), keyField)
+			if lastKey != nil {
+				condition := keyField.GreaterThan(lastKey.value)
+				if s.base.where != nil {
+					condition = s.base.where.And(condition)
+				}
+				page.base.where = condition
+			}
+			page.orderBys = []OrderBy{keyField}
+		} else {
+			page.offset = offset
+		}
+
+		cursor, err := page.FetchCursor()
+		if err != nil {
+			return err
+		}
+		batch := reflect.MakeSlice(batchType, 0, batchSize)
+		for cursor.Next() {
+			element := reflect.New(batchType.Elem())
+			dest := []interface{}{element.Interface()}
+			if keyField != nil {
+				lastKey = newScannedValue(keyField)
+				dest = append(dest, lastKey)
+			}
+			if err := cursor.Scan(dest...); err != nil {
+				_ = cursor.Close()
+				return err
+			}
+			batch = reflect.Append(batch, element.Elem())
+		}
+		if err := cursor.Close(); err != nil {
+			return err
+		}
+
+		if batch.Len() > 0 {
+			if result := callbackValue.Call([]reflect.Value{batch})[0]; !result.IsNil() {
+				return result.Interface().(error)
+			}
+		}
+		if batch.Len() < batchSize {
+			return nil
+		}
+	}
+}
+
+// getKeysetField returns the primary key to paginate FetchInBatches by, or nil if the statement cannot be
+// paginated by key.
+func (s selectStatus) getKeysetField() Field {
+	if len(s.orderBys) > 0 || s.lastUnion != nil || len(s.base.groupBys) > 0 || s.base.distinct ||
+		len(s.base.scope.Tables) != 1 || s.base.scope.lastJoin != nil {
+		return nil
+	}
+	table, ok := s.base.scope.Tables[0].(PrimaryKeyTable)
+	if !ok {
+		return nil
+	}
+	keyFields := table.GetPrimaryKeyFields()
+	if len(keyFields) != 1 {
+		return nil
+	}
+	return keyFields[0]
+}
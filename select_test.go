@@ -2,7 +2,10 @@ package sqlingo
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 )
 
 type tTable1 struct {
@@ -222,6 +225,59 @@ func TestFetchAll(t *testing.T) {
 	}
 }
 
+func TestNullable(t *testing.T) {
+	db := newMockDatabase()
+
+	oldColumnCount := sharedMockConn.columnCount
+	sharedMockConn.columnCount = 7
+	defer func() {
+		sharedMockConn.columnCount = oldColumnCount
+	}()
+
+	// the last column is always null
+	var a, c int
+	var b, f string
+	var d, e bool
+	g := 42
+	if _, err := db.Select(field1, field2, field1, field2, field1, field2, field1).From(Table1).
+		FetchFirst(&a, &b, &c, &d, &e, &f, &g); err == nil {
+		t.Error("should get error here")
+	}
+
+	if _, err := db.Select(field1, field2, field1, field2, field1, field2, Nullable(field1)).From(Table1).
+		FetchFirst(&a, &b, &c, &d, &e, &f, &g); err != nil {
+		t.Error(err)
+	}
+	assertLastSql(t, "SELECT `field1`, `field2`, `field1`, `field2`, `field1`, `field2`, `field1` FROM `table1`")
+	if a != 1 || g != 0 {
+		t.Error(a, g)
+	}
+
+	var tm time.Time
+	var ok bool
+	if _, err := db.Select(field1, field2, field1, field2, field1, field2, Nullable(field1).As("t")).From(Table1).
+		FetchFirst(&a, &b, &c, &d, &e, &f, &tm); err != nil || !tm.IsZero() {
+		t.Error(err, tm)
+	}
+	assertLastSql(t, "SELECT `field1`, `field2`, `field1`, `field2`, `field1`, `field2`, `field1` AS t FROM `table1`")
+	if _, err := db.Select(field1, field2, field1, field2, field1, field2, Nullable(field1)).From(Table1).
+		FetchFirst(&a, &b, &c, &d, &e, &f, &ok); err != nil || ok {
+		t.Error(err, ok)
+	}
+
+	// the wrapper keeps the column and the boolean type of the wrapped expression
+	nullable := Nullable(field1).(Field)
+	if name, err := getFieldName(nullable); err != nil || name != "field1" || nullable.GetTable() != table1 {
+		t.Error(name, err)
+	}
+	if !Nullable(field1.Equals(1)).(nullableExpression).isBool {
+		t.Error("should be boolean")
+	}
+	if _, err := getFieldName(Nullable(field1).As("t").(Field)); err == nil {
+		t.Error("an alias is not a column")
+	}
+}
+
 func TestRangeFunc(t *testing.T) {
 	db := newMockDatabase()
 
@@ -261,6 +317,22 @@ func TestLock(t *testing.T) {
 	assertLastSql(t, "SELECT 1 FROM `table1` FOR UPDATE NOWAIT")
 	_, _ = db.Select(1).From(table1).ForUpdateSkipLocked().FetchAll()
 	assertLastSql(t, "SELECT 1 FROM `table1` FOR UPDATE SKIP LOCKED")
+
+	_, _ = db.Select(1).From(table1).Join(table2).On(Raw("#1#")).ForUpdateOf(table1, table2).FetchAll()
+	assertLastSql(t, "SELECT 1 FROM `table1` JOIN `table2` ON #1# FOR UPDATE OF `table1`, `table2`")
+	table3 := NewTableInDatabase("db2", "table3")
+	_, _ = db.Select(1).From(table1).Join(table3).On(Raw("#1#")).ForUpdateOf(table3).FetchAll()
+	assertLastSql(t, "SELECT 1 FROM `table1` JOIN `db2`.`table3` ON #1# FOR UPDATE OF `db2`.`table3`")
+	derived := db.Select(1).From(table2).As("t2")
+	_, _ = db.Select(1).From(table1).Join(derived).On(Raw("#1#")).ForUpdateOf(derived).FetchAll()
+	assertLastSql(t, "SELECT 1 FROM `table1` JOIN (SELECT 1 FROM `table2`) AS `t2` ON #1# FOR UPDATE OF `t2`")
+	db.(*database).dialect = dialectPostgres
+	_, _ = db.Select(1).From(table1).Join(table2).On(Raw("#1#")).ForUpdateOf(table1).FetchAll()
+	assertLastSql(t, `SELECT 1 FROM "table1" JOIN "table2" ON #1# FOR UPDATE OF "table1"`)
+	db.(*database).dialect = dialectMSSQL
+	if _, err := db.Select(1).From(table1).ForUpdateOf(table1).FetchAll(); err == nil {
+		t.Error("should fail on SQL Server")
+	}
 }
 
 func TestUnion(t *testing.T) {
@@ -334,4 +406,490 @@ func Test_selectStatus_NaturalJoin(t *testing.T) {
 	assertLastSql(t, "SELECT * FROM `table1` JOIN `table2` ON <condition 1> NATURAL JOIN `table2`"+
 		" NATURAL JOIN `table3` LEFT JOIN `table4` ON <condition 3> WHERE <condition 2>")
 
+	_, _ = db.SelectFrom(table1).NaturalLeftJoin(table2).NaturalRightJoin(table3).FetchAll()
+	assertLastSql(t, "SELECT * FROM `table1` NATURAL LEFT JOIN `table2` NATURAL RIGHT JOIN `table3`")
+
+	db.(*database).dialect = dialectMSSQL
+	if _, err := db.SelectFrom(table1).NaturalJoin(table2).FetchAll(); err == nil {
+		t.Error("should fail on SQL Server")
+	}
+}
+
+func TestExcept(t *testing.T) {
+	db := newMockDatabase()
+
+	_, _ = db.Select(field1, field2).From(table1).
+		Except(db.Select(field3, field3).From(table2).Where(field3.Equals(1))).
+		FetchAll()
+	assertLastSql(t, "SELECT DISTINCT `l`.`c1` AS `field1`, `l`.`c2` AS `field2` FROM ("+
+		"SELECT `field1` AS c1, `field2` AS c2 FROM `table1`"+
+		") AS `l` LEFT JOIN (SELECT *, 1 AS `__m` FROM ("+
+		"SELECT `field3` AS c1, `field3` AS c2 FROM `table2` WHERE `field3` = 1"+
+		") AS `r0`) AS `r` ON `l`.`c1` <=> `r`.`c1` AND `l`.`c2` <=> `r`.`c2` WHERE `r`.`__m` IS NULL")
+
+	// the output columns keep their names for ordering
+	_, _ = db.Select(field1, field2.As("f2"), Raw("3")).From(table1).
+		Except(db.Select(field3, field3, 3).From(table2)).
+		OrderBy(field1).
+		FetchAll()
+	assertLastSql(t, "SELECT DISTINCT `l`.`c1` AS `field1`, `l`.`c2` AS `f2`, `l`.`c3` FROM ("+
+		"SELECT `field1` AS c1, `field2` AS c2, 3 AS c3 FROM `table1`"+
+		") AS `l` LEFT JOIN (SELECT *, 1 AS `__m` FROM ("+
+		"SELECT `field3` AS c1, `field3` AS c2, 3 AS c3 FROM `table2`"+
+		") AS `r0`) AS `r` ON `l`.`c1` <=> `r`.`c1` AND `l`.`c2` <=> `r`.`c2` AND `l`.`c3` <=> `r`.`c3` "+
+		"WHERE `r`.`__m` IS NULL ORDER BY `field1`")
+
+	// only the outermost emulation names its output columns
+	_, _ = db.Select(field1).From(table1).
+		Except(db.Select(field3).From(table2)).
+		Except(db.Select(field4).From(table3)).
+		FetchAll()
+	assertLastSql(t, "SELECT DISTINCT `l`.`c1` AS `field1` FROM ("+
+		"SELECT DISTINCT `l`.`c1` FROM (SELECT `field1` AS c1 FROM `table1`) AS `l` "+
+		"LEFT JOIN (SELECT *, 1 AS `__m` FROM (SELECT `field3` AS c1 FROM `table2`) AS `r0`) AS `r` "+
+		"ON `l`.`c1` <=> `r`.`c1` WHERE `r`.`__m` IS NULL"+
+		") AS `l` LEFT JOIN (SELECT *, 1 AS `__m` FROM (SELECT `field4` AS c1 FROM `table3`) AS `r0`) AS `r` "+
+		"ON `l`.`c1` <=> `r`.`c1` WHERE `r`.`__m` IS NULL")
+
+	_, _ = db.SelectFrom(Table1).
+		Except(db.Select(field3, 2).From(table2)).
+		OrderBy(Raw("1")).Limit(10).
+		FetchAll()
+	assertLastSql(t, "SELECT DISTINCT `l`.`c1` AS `field1`, `l`.`c2` AS `field2` FROM ("+
+		"SELECT `field1` AS c1, `field2` AS c2 FROM `table1`"+
+		") AS `l` LEFT JOIN (SELECT *, 1 AS `__m` FROM ("+
+		"SELECT `field3` AS c1, 2 AS c2 FROM `table2`"+
+		") AS `r0`) AS `r` ON `l`.`c1` <=> `r`.`c1` AND `l`.`c2` <=> `r`.`c2` WHERE `r`.`__m` IS NULL ORDER BY 1 LIMIT 10")
+
+	if _, err := db.Select(field1, field2).From(table1).
+		Except(db.Select(field3).From(table2)).
+		FetchAll(); err == nil {
+		t.Error("should fail on mismatched column counts")
+	}
+
+	db.(*database).dialect = dialectPostgres
+	_, _ = db.Select(field1).From(table1).
+		Except(db.Select(field3).From(table2)).
+		FetchAll()
+	assertLastSql(t, `SELECT "field1" FROM "table1" EXCEPT (SELECT "field3" FROM "table2")`)
+
+	db.(*database).dialect = dialectSqlite3
+	_, _ = db.Select(field1).From(table1).
+		Except(db.Select(field3).From(table2)).
+		FetchAll()
+	assertLastSql(t, `SELECT "field1" FROM "table1" EXCEPT SELECT "field3" FROM "table2"`)
+}
+
+func TestLimitWithTies(t *testing.T) {
+	db := newMockDatabase()
+
+	if _, err := db.SelectFrom(table1).OrderBy(field1.Desc()).LimitWithTies(3).FetchAll(); err == nil {
+		t.Error("should fail on MySQL")
+	}
+
+	db.(*database).dialect = dialectPostgres
+	_, _ = db.SelectFrom(table1).OrderBy(field1.Desc()).LimitWithTies(3).FetchAll()
+	assertLastSql(t, `SELECT * FROM "table1" ORDER BY "field1" DESC FETCH FIRST 3 ROWS WITH TIES`)
+
+	db.(*database).dialect = dialectMSSQL
+	_, _ = db.SelectDistinct(field1).From(table1).OrderBy(field1.Desc()).LimitWithTies(3).FetchAll()
+	assertLastSql(t, "SELECT DISTINCT TOP (3) WITH TIES [field1] FROM [table1] ORDER BY [field1] DESC")
+}
+
+func TestCartesianProductCheck(t *testing.T) {
+	db := newMockDatabase()
+
+	if _, err := db.SelectFrom(table1, table2).FetchAll(); err != nil {
+		t.Error(err)
+	}
+
+	db.EnableCartesianProductCheck(true)
+	defer db.EnableCartesianProductCheck(false)
+
+	if _, err := db.SelectFrom(table1, table2).FetchAll(); err == nil {
+		t.Error("should fail without any condition")
+	}
+	if _, err := db.SelectFrom(table1, table2).Where(field1.Equals(1)).FetchAll(); err == nil {
+		t.Error("should fail when table2 is not referenced")
+	}
+	_, _ = db.SelectFrom(table1, table2).Where(field1.Equals(field3)).FetchAll()
+	assertLastSql(t, "SELECT *, * FROM `table1`, `table2` WHERE `table1`.`field1` = `table2`.`field3`")
+	_, _ = db.SelectFrom(table1).Join(table2).On(field1.Equals(field3)).FetchAll()
+	assertLastSql(t, "SELECT * FROM `table1` JOIN `table2` ON `table1`.`field1` = `table2`.`field3`")
+
+	// a table whose name is a prefix of another is not taken as referenced
+	table10 := NewTable("table10")
+	field10 := NewNumberField(table10, "field1")
+	if _, err := db.SelectFrom(table1, table10, table2).Where(field10.Equals(field3)).FetchAll(); err == nil {
+		t.Error("should fail when table1 is not referenced")
+	}
+	// nor a table of the same name in another database
+	remote := NewTableInDatabase("db2", "table1")
+	if _, err := db.SelectFrom(table1, remote).Where(NewNumberField(remote, "field1").Equals(1)).FetchAll(); err == nil {
+		t.Error("should fail when table1 is not referenced")
+	}
+	if _, err := db.SelectFrom(table1, remote).Where(field1.Equals(NewNumberField(remote, "field1"))).FetchAll(); err != nil {
+		t.Error(err)
+	}
+
+	totals := db.Select(field3).From(table2).As("totals")
+	if _, err := db.SelectFrom(table1, totals).Where(field1.Equals(totals.Column("field3"))).FetchAll(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGroupByHaving(t *testing.T) {
+	db := newMockDatabase()
+
+	_, _ = db.Select(field1, Count(1)).From(table1).
+		Where(field2.GreaterThan(0)).
+		GroupBy(field1, field2).
+		Having(Count(1).GreaterThan(1), Sum(field2).LessThan(100)).
+		OrderBy(field1).
+		Limit(10).
+		FetchAll()
+	assertLastSql(t, "SELECT `field1`, COUNT(1) FROM `table1` WHERE `field2` > 0 "+
+		"GROUP BY `field1`, `field2` HAVING COUNT(1) > 1 AND SUM(`field2`) < 100 ORDER BY `field1` LIMIT 10")
+
+	_, _ = db.Select(field1).From(table1).GroupBy(field1).Having().FetchAll()
+	assertLastSql(t, "SELECT `field1` FROM `table1` GROUP BY `field1`")
+}
+
+func TestGroupByHavingCountDistinct(t *testing.T) {
+	db := newMockDatabase()
+	oldColumnCount := sharedMockConn.columnCount
+	sharedMockConn.columnCount = 2
+	defer func() {
+		sharedMockConn.columnCount = oldColumnCount
+	}()
+
+	// SELECT dept, COUNT(DISTINCT title) AS n FROM emp GROUP BY dept HAVING COUNT(DISTINCT title) > 3
+	n := field2.CountDistinct()
+	var rows []struct {
+		Dept string
+		N    int
+	}
+	if _, err := db.Select(field1, n.As("n")).From(table1).GroupBy(field1).Having(n.GreaterThan(3)).FetchAll(&rows); err != nil {
+		t.Error(err)
+	}
+	assertLastSql(t, "SELECT `field1`, COUNT(DISTINCT `field2`) AS n FROM `table1` "+
+		"GROUP BY `field1` HAVING COUNT(DISTINCT `field2`) > 3")
+	if len(rows) != 10 || rows[0].Dept != "1" || rows[0].N != 1 {
+		t.Error(rows)
+	}
+
+	// the same by the Distinct marker, with the aggregate ordering the groups
+	query := db.Select(field1, field2.Distinct().Count().As("n")).From(table1).Where(field1.IsNotNull()).
+		GroupBy(field1).Having(field2.Distinct().Count().GreaterThan(3), Count(1).LessThan(100)).
+		OrderBy(field2.Distinct().Count().Desc())
+	assertSQLWithArgs(t, query,
+		"SELECT `field1`, COUNT(DISTINCT `field2`) AS n FROM `table1` WHERE `field1` IS NOT NULL GROUP BY `field1` "+
+			"HAVING COUNT(DISTINCT `field2`) > ? AND COUNT(?) < ? ORDER BY COUNT(DISTINCT `field2`) DESC", 3, 1, 100)
+
+	db.(*database).dialect = dialectPostgres
+	_, _ = db.Select(field1, n.As("n")).From(table1).GroupBy(field1).Having(n.GreaterThan(3)).FetchAll()
+	assertLastSql(t, `SELECT "field1", COUNT(DISTINCT "field2") AS n FROM "table1" `+
+		`GROUP BY "field1" HAVING COUNT(DISTINCT "field2") > 3`)
+}
+
+func TestLimitOffset(t *testing.T) {
+	db := newMockDatabase()
+
+	_, _ = db.SelectFrom(table1).OrderBy(field1).Limit(10).Offset(20).FetchAll()
+	assertLastSql(t, "SELECT * FROM `table1` ORDER BY `field1` LIMIT 10 OFFSET 20")
+
+	db.(*database).dialect = dialectPostgres
+	_, _ = db.SelectFrom(table1).Limit(10).Offset(20).FetchAll()
+	assertLastSql(t, `SELECT * FROM "table1" LIMIT 10 OFFSET 20`)
+
+	db.(*database).dialect = dialectMSSQL
+	_, _ = db.SelectFrom(table1).OrderBy(field1).Limit(10).Offset(20).FetchAll()
+	assertLastSql(t, "SELECT * FROM [table1] ORDER BY [field1] OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY")
+	_, _ = db.SelectFrom(table1).OrderBy(field1).Limit(10).FetchAll()
+	assertLastSql(t, "SELECT * FROM [table1] ORDER BY [field1] OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY")
+	_, _ = db.SelectFrom(table1).OrderBy(field1).FetchAll()
+	assertLastSql(t, "SELECT * FROM [table1] ORDER BY [field1]")
+	if _, err := db.SelectFrom(table1).Limit(10).Offset(20).FetchAll(); err == nil {
+		t.Error("should fail without ORDER BY")
+	}
+}
+
+func TestJoinTypes(t *testing.T) {
+	db := newMockDatabase()
+
+	_, _ = db.Select(field1, field3, field4).From(table1).
+		LeftJoin(table2).On(field1.Equals(field3)).
+		RightJoin(table3).On(field1.Equals(field4)).
+		CrossJoin(table2).
+		FetchAll()
+	assertLastSql(t, "SELECT `table1`.`field1`, `table2`.`field3`, `table3`.`field4` FROM `table1` "+
+		"LEFT JOIN `table2` ON `table1`.`field1` = `table2`.`field3` "+
+		"RIGHT JOIN `table3` ON `table1`.`field1` = `table3`.`field4` "+
+		"CROSS JOIN `table2`")
+
+	if _, err := db.SelectFrom(table1).FullJoin(table2).On(field1.Equals(field3)).FetchAll(); err == nil {
+		t.Error("should fail on MySQL")
+	}
+
+	db.(*database).dialect = dialectPostgres
+	_, _ = db.SelectFrom(table1).FullJoin(table2).On(field1.Equals(field3)).FetchAll()
+	assertLastSql(t, `SELECT * FROM "table1" FULL JOIN "table2" ON "table1"."field1" = "table2"."field3"`)
+}
+
+func TestFetchInBatches(t *testing.T) {
+	db := newMockDatabase()
+
+	sharedMockConn.columnCount = 2
+	defer func() {
+		sharedMockConn.columnCount = 7
+		sharedMockConn.rowCount = 10
+	}()
+
+	// the mock returns 4, 4 and then 2 rows
+	var sqls []string
+	db.SetInterceptor(func(ctx context.Context, sql string, invoker InvokerFunc) error {
+		sharedMockConn.rowCount = []int{4, 4, 2}[len(sqls)%3]
+		err := invoker(ctx, sql)
+		sqls = append(sqls, sharedMockConn.lastSql)
+		return err
+	})
+	defer db.SetInterceptor(nil)
+
+	type record struct {
+		F1 string
+		F2 int
+	}
+	var sizes []int
+	err := db.Select(field1, field2).From(Table1).Where(field1.GreaterThan(0)).
+		FetchInBatches(4, func(batch []record) error {
+			sizes = append(sizes, len(batch))
+			return nil
+		})
+	if err != nil {
+		t.Error(err)
+	}
+	if len(sizes) != 3 || sizes[0] != 4 || sizes[1] != 4 || sizes[2] != 2 {
+		t.Error(sizes)
+	}
+	assertEqual(t, strings.Join(sqls, "\n"), strings.Join([]string{
+		"SELECT `field1`, `field2` FROM `table1` WHERE `field1` > 0 LIMIT 4",
+		"SELECT `field1`, `field2` FROM `table1` WHERE `field1` > 0 LIMIT 4 OFFSET 4",
+		"SELECT `field1`, `field2` FROM `table1` WHERE `field1` > 0 LIMIT 4 OFFSET 8",
+	}, "\n"))
+
+	// paginated by the primary key
+	sqls = nil
+	sizes = nil
+	err = db.Select(Test.F2).From(Test).FetchInBatches(4, func(batch []string) error {
+		sizes = append(sizes, len(batch))
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	if len(sizes) != 3 || sizes[0] != 4 || sizes[1] != 4 || sizes[2] != 2 {
+		t.Error(sizes)
+	}
+	assertEqual(t, strings.Join(sqls, "\n"), strings.Join([]string{
+		"SELECT `f2`, `f1` FROM `test` ORDER BY `f1` LIMIT 4",
+		"SELECT `f2`, `f1` FROM `test` WHERE `f1` > 4 ORDER BY `f1` LIMIT 4",
+		"SELECT `f2`, `f1` FROM `test` WHERE `f1` > 4 ORDER BY `f1` LIMIT 4",
+	}, "\n"))
+
+	sqls = nil
+	errStop := errors.New("stop")
+	if err := db.Select(field1, field2).From(Table1).FetchInBatches(4, func(batch []record) error {
+		return errStop
+	}); err != errStop || len(sqls) != 1 {
+		t.Error(err, sqls)
+	}
+
+	if err := db.Select(field1).From(Table1).FetchInBatches(0, func([]string) error { return nil }); err == nil {
+		t.Error("should fail on non-positive batch size")
+	}
+	if err := db.Select(field1).From(Table1).FetchInBatches(1, func() error { return nil }); err == nil {
+		t.Error("should fail on a callback without a slice argument")
+	}
+	if err := db.Select(field1).From(Table1).Limit(10).FetchInBatches(1, func([]string) error { return nil }); err == nil {
+		t.Error("should fail with LIMIT")
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	db := newMockDatabase()
+
+	query := db.Select(field1).From(table1).
+		Union(db.Select(field3).From(table2)).
+		UnionAll(db.Select(field4).From(table3).Where(field4.GreaterThan(1))).
+		OrderBy(Raw("1")).
+		Limit(5)
+	_, _ = query.FetchAll()
+	assertLastSql(t, "SELECT `field1` FROM `table1` "+
+		"UNION (SELECT `field3` FROM `table2`) "+
+		"UNION ALL (SELECT `field4` FROM `table3` WHERE `field4` > 1) ORDER BY 1 LIMIT 5")
+
+	_, _ = db.Select(field3).From(table2).Where(field3.In(query)).FetchAll()
+	assertLastSql(t, "SELECT `field3` FROM `table2` WHERE `field3` IN (SELECT `field1` FROM `table1` "+
+		"UNION (SELECT `field3` FROM `table2`) "+
+		"UNION ALL (SELECT `field4` FROM `table3` WHERE `field4` > 1) ORDER BY 1 LIMIT 5)")
+
+	_, _ = db.Select(field1).From(table1).Union(db.Select(field3).From(table2)).Count()
+	assertLastSql(t, "SELECT COUNT(1) FROM (SELECT `field1` FROM `table1` UNION (SELECT `field3` FROM `table2`)) AS t")
+
+	if _, err := db.Select(field1).From(table1).Intersect(db.Select(field3).From(table2)).FetchAll(); err == nil {
+		t.Error("should fail on MySQL")
+	}
+
+	db.(*database).dialect = dialectPostgres
+	_, _ = db.Select(field1).From(table1).
+		Intersect(db.Select(field3).From(table2)).
+		Except(db.Select(field4).From(table3)).
+		FetchAll()
+	assertLastSql(t, `SELECT "field1" FROM "table1" INTERSECT (SELECT "field3" FROM "table2") EXCEPT (SELECT "field4" FROM "table3")`)
+}
+
+func TestMSSQLFetch(t *testing.T) {
+	db := newMockDatabase()
+	db.(*database).dialect = dialectMSSQL
+
+	sharedMockConn.columnCount = 2
+	defer func() {
+		sharedMockConn.columnCount = 7
+	}()
+
+	var rows []struct {
+		F1 string
+		F2 string
+	}
+	if _, err := db.Select(field1, field2).From(table1).
+		Where(field1.GreaterThan(0)).
+		OrderBy(field2.Desc()).
+		Limit(5).Offset(10).
+		FetchAll(&rows); err != nil {
+		t.Error(err)
+	}
+	assertLastSql(t, "SELECT [field1], [field2] FROM [table1] WHERE [field1] > 0 "+
+		"ORDER BY [field2] DESC OFFSET 10 ROWS FETCH NEXT 5 ROWS ONLY")
+	if len(rows) != 10 {
+		t.Error(len(rows))
+	}
+
+	_, _ = db.SelectDistinct(field1).From(table1).Limit(3).FetchAll()
+	assertLastSql(t, "SELECT DISTINCT TOP (3) [field1] FROM [table1]")
+
+	sharedMockConn.columnCount = 1
+	var f1 string
+	if ok, err := db.Select(field1).From(table1).FetchFirst(&f1); !ok || err != nil {
+		t.Error(ok, err)
+	}
+	assertLastSql(t, "SELECT [field1] FROM [table1]")
+
+	if _, err := db.SelectFrom(table1).Where(field1.Equals(1)).Limit(1).Exists(); err != nil {
+		t.Error(err)
+	}
+	assertLastSql(t, "SELECT CASE WHEN EXISTS (SELECT TOP (1) * FROM [table1] WHERE [field1] = 1) THEN 1 ELSE 0 END")
+}
+
+func TestSelectDuplicateColumnNames(t *testing.T) {
+	db := newMockDatabase()
+	otherField1 := NewNumberField(table2, "field1")
+
+	_, _ = db.Select(field1, otherField1).From(table1, table2).FetchAll()
+	assertLastSql(t, "SELECT `table1`.`field1`, `table2`.`field1` FROM `table1`, `table2`")
+
+	db.EnableDuplicateColumnAliases(true)
+	_, _ = db.Select(field1, field2, otherField1, field1.Add(1)).From(table1).Join(table2).On(field1.Equals(otherField1)).FetchAll()
+	assertLastSql(t, "SELECT `table1`.`field1` AS `table1_field1`, `table1`.`field2`, `table2`.`field1` AS `table2_field1`, `table1`.`field1` + 1 "+
+		"FROM `table1` JOIN `table2` ON `table1`.`field1` = `table2`.`field1`")
+
+	// the columns of a derived table are named after the aliases
+	joined := db.Select(field1, otherField1).From(table1, table2).As("j")
+	_, _ = db.Select(joined).FetchAll()
+	assertLastSql(t, "SELECT `table1_field1`, `table2_field1` FROM "+
+		"(SELECT `table1`.`field1` AS `table1_field1`, `table2`.`field1` AS `table2_field1` FROM `table1`, `table2`) AS `j`")
+
+	_, _ = db.Select(field1, otherField1.As("other")).From(table1, table2).FetchAll()
+	assertLastSql(t, "SELECT `table1`.`field1`, `table2`.`field1` AS other FROM `table1`, `table2`")
+
+	_, _ = db.Select(field1, field1).From(table1).FetchAll()
+	assertLastSql(t, "SELECT `field1`, `field1` FROM `table1`")
+
+	// a nullable field is aliased as well, and stays nullable
+	fields := fieldList{Nullable(field1).(Field), otherField1}.aliasDuplicates()
+	if _, ok := fields[0].(nullableExpression); !ok {
+		t.Errorf("%T", fields[0])
+	}
+	_, _ = db.Select(Nullable(field1), otherField1).From(table1, table2).FetchAll()
+	assertLastSql(t, "SELECT `table1`.`field1` AS `table1_field1`, `table2`.`field1` AS `table2_field1` FROM `table1`, `table2`")
+}
+
+func TestMaxFetchRows(t *testing.T) {
+	db := newMockDatabase()
+	sharedMockConn.columnCount = 1
+	defer func() {
+		sharedMockConn.columnCount = 7
+	}()
+
+	db.SetMaxFetchRows(20)
+	var f1s []int
+	if _, err := db.Select(field1).From(Table1).FetchAll(&f1s); err != nil {
+		t.Error(err)
+	}
+	assertLastSql(t, "SELECT `field1` FROM `table1` LIMIT 21")
+
+	// reaching the limit is not an error, only exceeding it
+	db.SetMaxFetchRows(10)
+	if rows, err := db.Select(field1).From(Table1).FetchAll(&f1s); err != nil || rows != 10 {
+		t.Error(rows, err)
+	}
+	assertLastSql(t, "SELECT `field1` FROM `table1` LIMIT 11")
+
+	// the mock returns 10 rows regardless of the limit
+	db.SetMaxFetchRows(9)
+	if rows, err := db.Select(field1).From(Table1).FetchAll(&f1s); err == nil || rows != 9 {
+		t.Error(rows, err)
+	}
+
+	var seqErr error
+	seqRows := 0
+	db.Select(field1).From(Table1).FetchSeq()(func(row Scanner) bool {
+		var f1 int
+		if seqErr = row.Scan(&f1); seqErr != nil {
+			return false
+		}
+		seqRows++
+		return true
+	})
+	if seqErr == nil || seqRows != 9 {
+		t.Error(seqRows, seqErr)
+	}
+
+	cursor, err := db.Select(field1).From(Table1).FetchCursor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cursorRows := 0
+	for cursor.Next() {
+		cursorRows++
+	}
+	if err := cursor.Close(); err == nil || cursorRows != 9 {
+		t.Error(cursorRows, err)
+	}
+
+	// an explicit limit is kept and not checked
+	if _, err := db.Select(field1).From(Table1).Limit(100).FetchAll(&f1s); err != nil {
+		t.Error(err)
+	}
+	assertLastSql(t, "SELECT `field1` FROM `table1` LIMIT 100")
+
+	if _, err := db.Select(field1).From(Table1).FetchFirst(new(int)); err != nil {
+		t.Error(err)
+	}
+	assertLastSql(t, "SELECT `field1` FROM `table1`")
+
+	db.SetMaxFetchRows(0)
+	if _, err := db.Select(field1).From(Table1).FetchAll(&f1s); err != nil {
+		t.Error(err)
+	}
+	assertLastSql(t, "SELECT `field1` FROM `table1`")
 }
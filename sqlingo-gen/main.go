@@ -14,6 +14,7 @@ func main() {
 		"go get -u github.com/lqs/sqlingo/sqlingo-gen-mysql",
 		"go get -u github.com/lqs/sqlingo/sqlingo-gen-sqlite3",
 		"go get -u github.com/lqs/sqlingo/sqlingo-gen-postgres",
+		"go get -u github.com/lqs/sqlingo/sqlingo-gen-mssql",
 		"\u001b[0m",
 	}
 	_, _ = fmt.Fprintln(os.Stderr, strings.Join(warningLines, "\n"))
@@ -0,0 +1,207 @@
+package sqlingo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+type syncStatus struct {
+	database      *database
+	table         Table
+	models        interface{}
+	keyFields     []Field
+	deleteMissing bool
+	batchSize     int
+	ctx           context.Context
+}
+
+type syncWithModels interface {
+	toSyncWithContext
+	toSyncFinal
+	DeleteMissing() syncWithModels
+	BatchSize(size int) syncWithModels
+}
+
+type toSyncWithContext interface {
+	WithContext(ctx context.Context) toSyncFinal
+}
+
+type toSyncFinal interface {
+	Execute() (result sql.Result, err error)
+}
+
+// Sync reconciles the table with the models matched by the key fields: the new rows are inserted and
+// the existing ones are updated. With DeleteMissing, the rows not in the models are deleted as well.
+// The statements are executed in a transaction. The primary key of the table is used if no key fields are given.
+func (d *database) Sync(table Table, models interface{}, keyFields ...Field) syncWithModels {
+	return syncStatus{database: d, table: table, models: models, keyFields: keyFields}
+}
+
+// DeleteMissing deletes the rows of the table whose keys are not in the models.
+func (s syncStatus) DeleteMissing() syncWithModels {
+	s.deleteMissing = true
+	return s
+}
+
+// BatchSize splits the upsert into batches of at most size rows.
+func (s syncStatus) BatchSize(size int) syncWithModels {
+	s.batchSize = size
+	return s
+}
+
+func (s syncStatus) WithContext(ctx context.Context) toSyncFinal {
+	s.ctx = ctx
+	return s
+}
+
+func (s syncStatus) Execute() (result sql.Result, err error) {
+	if len(s.keyFields) == 0 {
+		if table, ok := s.table.(PrimaryKeyTable); ok {
+			s.keyFields = table.GetPrimaryKeyFields()
+		}
+	}
+	if len(s.keyFields) == 0 {
+		return nil, errors.New("sync requires key fields")
+	}
+	var models []Model
+	if err := addModel(&models, s.models); err != nil {
+		return nil, err
+	}
+
+	if s.database.tx == nil {
+		err = s.database.BeginTx(s.ctx, nil, func(tx Transaction) error {
+			s.database = tx.(*database)
+			result, err = s.execute(models)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		return
+	}
+	return s.execute(models)
+}
+
+func (s syncStatus) execute(models []Model) (sql.Result, error) {
+	var total batchResult
+	if len(models) > 0 {
+		upsert, err := s.getUpsert(models)
+		if err != nil {
+			return nil, err
+		}
+		r, err := upsert.WithContext(s.ctx).Execute()
+		if err != nil {
+			return nil, err
+		}
+		total.lastInsertId, total.lastInsertIdErr = r.LastInsertId()
+		if total.rowsAffected, err = r.RowsAffected(); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.deleteMissing {
+		var deleteStatement toDeleteFinal
+		if len(models) == 0 {
+			deleteStatement = s.database.DeleteFrom(s.table).DeleteAll().WithContext(s.ctx)
+		} else {
+			condition, err := s.getMissingCondition(models)
+			if err != nil {
+				return nil, err
+			}
+			deleteStatement = s.database.DeleteFrom(s.table).Where(condition).WithContext(s.ctx)
+		}
+		r, err := deleteStatement.Execute()
+		if err != nil {
+			return nil, err
+		}
+		rowsAffected, err := r.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		total.rowsAffected += rowsAffected
+	}
+	return total, nil
+}
+
+// getUpsert builds the insert of the models, which updates the non-key fields of the existing rows.
+func (s syncStatus) getUpsert(models []Model) (toInsertWithDuplicateKey, error) {
+	keyNames := make(map[string]bool, len(s.keyFields))
+	for _, field := range s.keyFields {
+		name, err := getFieldName(field)
+		if err != nil {
+			return nil, err
+		}
+		keyNames[name] = true
+	}
+
+	modelValues := make([]interface{}, len(models))
+	for i, model := range models {
+		modelValues[i] = model
+	}
+	onConflict := s.database.InsertInto(s.table).Models(modelValues...).OnConflict(s.keyFields...)
+
+	var upsert insertWithOnConflictDoUpdate
+	for _, field := range s.table.GetFields() {
+		name, err := getFieldName(field)
+		if err != nil {
+			return nil, err
+		}
+		if keyNames[name] {
+			continue
+		}
+		if upsert == nil {
+			upsert = onConflict.DoUpdateSet(field, Excluded(field))
+		} else {
+			upsert = upsert.DoUpdateSet(field, Excluded(field))
+		}
+	}
+	if upsert == nil {
+		return onConflict.DoNothing().BatchSize(s.batchSize), nil
+	}
+	return upsert.BatchSize(s.batchSize), nil
+}
+
+// getMissingCondition builds the condition matching the rows whose keys are not in the models.
+func (s syncStatus) getMissingCondition(models []Model) (BooleanExpression, error) {
+	fieldIndexes := make(map[string]int)
+	for i, field := range s.table.GetFields() {
+		name, err := getFieldName(field)
+		if err != nil {
+			return nil, err
+		}
+		fieldIndexes[name] = i
+	}
+	keyIndexes := make([]int, len(s.keyFields))
+	for i, field := range s.keyFields {
+		name, err := getFieldName(field)
+		if err != nil {
+			return nil, err
+		}
+		index, ok := fieldIndexes[name]
+		if !ok {
+			return nil, errors.New("unknown key field " + name)
+		}
+		keyIndexes[i] = index
+	}
+
+	if len(s.keyFields) == 1 {
+		keys := make([]interface{}, len(models))
+		for i, model := range models {
+			keys[i] = model.GetValues()[keyIndexes[0]]
+		}
+		return s.keyFields[0].NotIn(keys...), nil
+	}
+
+	// composite keys are matched row by row
+	matches := make([]BooleanExpression, len(models))
+	for i, model := range models {
+		values := model.GetValues()
+		conditions := make([]BooleanExpression, len(s.keyFields))
+		for j, field := range s.keyFields {
+			conditions[j] = field.Equals(values[keyIndexes[j]])
+		}
+		matches[i] = And(conditions...)
+	}
+	return Or(matches...).Not(), nil
+}
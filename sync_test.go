@@ -0,0 +1,79 @@
+package sqlingo
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+)
+
+func TestSync(t *testing.T) {
+	db := newMockDatabase()
+	sharedMockConn.execResult = driver.RowsAffected(1)
+	defer func() {
+		sharedMockConn.execResult = nil
+	}()
+	var sqls []string
+	db.SetInterceptor(func(ctx context.Context, sql string, invoker InvokerFunc) error {
+		sqls = append(sqls, sql)
+		return invoker(ctx, sql)
+	})
+
+	models := []TestModel{{1, "a"}, {2, "b"}}
+	result, err := db.Sync(Test, models, Test.F1).DeleteMissing().Execute()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected != 2 {
+		t.Error(rowsAffected)
+	}
+	if len(sqls) != 2 {
+		t.Fatal(sqls)
+	}
+	assertEqual(t, sqls[0], "INSERT INTO `test` (`f1`, `f2`) VALUES (1, 'a'), (2, 'b') ON DUPLICATE KEY UPDATE `f2` = VALUES(`f2`)")
+	assertEqual(t, sqls[1], "DELETE FROM `test` WHERE `f1` NOT IN (1, 2)")
+
+	sqls = nil
+	db.(*database).dialect = dialectPostgres
+	if _, err := db.Sync(Test, &models, Test.F1, Test.F2).DeleteMissing().WithContext(context.Background()).Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if len(sqls) != 2 {
+		t.Fatal(sqls)
+	}
+	assertEqual(t, sqls[0], `INSERT INTO "test" ("f1", "f2") VALUES (1, 'a'), (2, 'b') ON CONFLICT ("f1", "f2") DO NOTHING`)
+	assertEqual(t, sqls[1], `DELETE FROM "test" WHERE NOT ("f1" = 1 AND "f2" = 'a' OR "f1" = 2 AND "f2" = 'b')`)
+
+	sqls = nil
+	if _, err := db.Sync(Test, []TestModel{}, Test.F1).DeleteMissing().Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if len(sqls) != 1 {
+		t.Fatal(sqls)
+	}
+	assertEqual(t, sqls[0], `DELETE FROM "test"`)
+
+	sqls = nil
+	if _, err := db.Sync(Test, models).Execute(); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, sqls[0], `INSERT INTO "test" ("f1", "f2") VALUES (1, 'a'), (2, 'b') ON CONFLICT ("f1") DO UPDATE SET "f2" = excluded."f2"`)
+
+	// every statement runs in the context of the sync
+	type contextKey struct{}
+	var values []interface{}
+	db.SetInterceptor(func(ctx context.Context, sql string, invoker InvokerFunc) error {
+		values = append(values, ctx.Value(contextKey{}))
+		return invoker(ctx, sql)
+	})
+	ctx := context.WithValue(context.Background(), contextKey{}, "sync")
+	if _, err := db.Sync(Test, models, Test.F1).DeleteMissing().WithContext(ctx).Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != 2 || values[0] != "sync" || values[1] != "sync" {
+		t.Error(values)
+	}
+
+	if _, err := db.Sync(Table1, models).Execute(); err == nil {
+		t.Error("should fail without key fields")
+	}
+}
@@ -1,5 +1,7 @@
 package sqlingo
 
+import "fmt"
+
 // Table is the interface of a generated table.
 type Table interface {
 	GetName() string
@@ -7,6 +9,12 @@ type Table interface {
 	GetFields() []Field
 }
 
+// PrimaryKeyTable is the interface of a generated table that knows its primary key.
+type PrimaryKeyTable interface {
+	Table
+	GetPrimaryKeyFields() []Field
+}
+
 type actualTable interface {
 	Table
 	GetFieldsSQL() string
@@ -36,6 +44,54 @@ func NewTable(name string) Table {
 	return table{name: name, sqlDialects: quoteIdentifier(name)}
 }
 
+// NewTableInDatabase creates a reference to a table qualified with its database, e.g. `db2`.`table` on MySQL,
+// for cross-database queries. It should only be called from generated code.
+func NewTableInDatabase(databaseName string, name string) Table {
+	databaseSqlArray := quoteIdentifier(databaseName)
+	nameSqlArray := quoteIdentifier(name)
+	var sqlDialects dialectArray
+	for dialect := dialect(0); dialect < dialectCount; dialect++ {
+		sqlDialects[dialect] = databaseSqlArray[dialect] + "." + nameSqlArray[dialect]
+	}
+	return table{name: name, sqlDialects: sqlDialects}
+}
+
+// AllFieldsExcept returns the fields of the table except the excluded ones, e.g. for selecting all but the large
+// columns with db.Select(AllFieldsExcept(t, t.Content)).From(t). Generated tables have it as a method.
+func AllFieldsExcept(table Table, excluded ...Field) []Field {
+	excludedNames := make(map[string]bool, len(excluded))
+	for _, field := range excluded {
+		if name, err := getFieldName(field); err == nil {
+			excludedNames[field.GetTable().GetName()+"."+name] = true
+		}
+	}
+	fields := make([]Field, 0, len(table.GetFields()))
+	for _, field := range table.GetFields() {
+		name, err := getFieldName(field)
+		if err == nil && excludedNames[field.GetTable().GetName()+"."+name] {
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// getTableSQLArray returns the quoted, possibly qualified, name of the table in each dialect.
+func getTableSQLArray(t Table) dialectArray {
+	if t, ok := t.(table); ok {
+		return t.sqlDialects
+	}
+	return quoteIdentifier(t.GetName())
+}
+
+// DerivedTable is a subquery in FROM with an alias, which is created by As of a select.
+type DerivedTable interface {
+	Table
+	// Column creates a reference to a column of the derived table.
+	Column(name string) Field
+}
+
+// derivedTable is the subquery wrapped by Count, which exposes the fields of its select list.
 type derivedTable struct {
 	name         string
 	selectStatus selectStatus
@@ -46,10 +102,77 @@ func (t derivedTable) GetName() string {
 }
 
 func (t derivedTable) GetSQL(scope scope) string {
-	sql, _ := t.selectStatus.GetSQL()
-	return "(" + sql + ") AS " + t.name
+	sql, _ := t.getSQL(scope)
+	return sql
+}
+
+// getSQL is GetSQL with the error of building the subquery, which is reported by the enclosing select.
+func (t derivedTable) getSQL(scope scope) (string, error) {
+	sql, err := getStatementSQL(scope.args, t.selectStatus)
+	if err != nil {
+		return "", err
+	}
+	return "(" + sql + ") AS " + t.name, nil
 }
 
 func (t derivedTable) GetFields() []Field {
 	return activeSelectBase(&t.selectStatus).fields
 }
+
+// aliasedTable is a select made a derived table by As. Its alias is quoted, as it may be case-sensitive
+// on PostgreSQL, and its columns are qualified by the alias rather than the tables inside.
+type aliasedTable struct {
+	derivedTable
+}
+
+func (t aliasedTable) GetSQL(scope scope) string {
+	sql, _ := t.getSQL(scope)
+	return sql
+}
+
+func (t aliasedTable) getSQL(scope scope) (string, error) {
+	if !isPlainIdentifier(t.name) {
+		return "", fmt.Errorf("invalid derived table alias %q", t.name)
+	}
+	sql, err := getStatementSQL(scope.args, t.selectStatus)
+	if err != nil {
+		return "", err
+	}
+	return "(" + sql + ") AS " + quoteIdentifier(t.name)[getDialect(scope)], nil
+}
+
+// GetFields returns the columns of the derived table, named after the fields or aliases in the select list.
+func (t aliasedTable) GetFields() []Field {
+	return getSelectColumns(t, t.selectStatus)
+}
+
+func (t aliasedTable) Column(name string) Field {
+	return newField(t, name, columnUnknown)
+}
+
+// getSelectColumns returns the columns of the table named after the fields or aliases in the select list
+// of the query. The column names of a compound query are taken from its first member.
+func getSelectColumns(table Table, query selectStatus) []Field {
+	fields := make([]Field, 0, len(query.base.fields))
+	for _, field := range query.base.getFields() {
+		if nullable, ok := field.(nullableExpression); ok {
+			if alias, ok := nullable.wrapped.(aliasExpression); ok {
+				fields = append(fields, newField(table, alias.alias, columnUnknown))
+				continue
+			}
+		}
+		if alias, ok := field.(aliasExpression); ok {
+			fields = append(fields, newField(table, alias.alias, columnUnknown))
+			continue
+		}
+		if field.GetTable() == nil {
+			continue
+		}
+		name, err := getFieldName(field)
+		if err != nil {
+			continue
+		}
+		fields = append(fields, newField(table, name, field.getColumn().kind))
+	}
+	return fields
+}
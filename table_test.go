@@ -1,6 +1,9 @@
 package sqlingo
 
-import "testing"
+import (
+	"errors"
+	"testing"
+)
 
 func TestTable(t *testing.T) {
 	table := table{}
@@ -31,3 +34,76 @@ func TestDerivedTable(t *testing.T) {
 		t.Error(sql)
 	}
 }
+
+func TestSelectAs(t *testing.T) {
+	db := newMockDatabase()
+	totals := db.Select(field1, field2.Sum().As("total")).From(table1).GroupBy(field1).As("totals")
+	total := NewNumberField(totals, "total")
+
+	_, _ = db.SelectFrom(totals).Where(total.GreaterThan(10)).FetchAll()
+	assertLastSql(t, "SELECT * FROM (SELECT `field1`, SUM(`field2`) AS total FROM `table1` GROUP BY `field1`) AS `totals` "+
+		"WHERE `total` > 10")
+
+	_, _ = db.Select(totals).FetchAll()
+	assertLastSql(t, "SELECT `field1`, `total` FROM (SELECT `field1`, SUM(`field2`) AS total FROM `table1` GROUP BY `field1`) AS `totals`")
+
+	_, _ = db.Select(field3, totals.Column("total")).From(table2).
+		Join(totals).On(totals.Column("field1").Equals(field3)).FetchAll()
+	assertLastSql(t, "SELECT `table2`.`field3`, `totals`.`total` FROM `table2` "+
+		"JOIN (SELECT `field1`, SUM(`field2`) AS total FROM `table1` GROUP BY `field1`) AS `totals` "+
+		"ON `totals`.`field1` = `table2`.`field3`")
+
+	if _, err := db.SelectFrom(db.Select(field1).From(table1).As("")).FetchAll(); err == nil {
+		t.Error("should fail without alias")
+	}
+	if _, err := db.SelectFrom(db.Select(field1).From(table1).As("t) x")).FetchAll(); err == nil {
+		t.Error("should fail with an invalid alias")
+	}
+	if _, err := db.SelectFrom(db.Select(errorExpression(errors.New("error"))).As("t")).FetchAll(); err == nil {
+		t.Error("should fail with the error of the subquery")
+	}
+
+	db.EnableParameterizedQuery(true)
+	filtered := db.SelectFrom(table1).Where(field1.Equals(1)).As("t")
+	assertSQLWithArgs(t, db.SelectFrom(filtered).Where(filtered.Column("field2").Equals(2)),
+		"SELECT * FROM (SELECT * FROM `table1` WHERE `field1` = ?) AS `t` WHERE `field2` = ?", 1, 2)
+	db.EnableParameterizedQuery(false)
+
+	db.(*database).dialect = dialectPostgres
+	upper := db.Select(field1).From(table1).As("Totals")
+	_, _ = db.Select(upper.Column("field1")).From(table2).Join(upper).On(upper.Column("field1").Equals(field3)).FetchAll()
+	assertLastSql(t, `SELECT "Totals"."field1" FROM "table2" JOIN (SELECT "field1" FROM "table1") AS "Totals" `+
+		`ON "Totals"."field1" = "table2"."field3"`)
+}
+
+func TestTableInDatabase(t *testing.T) {
+	db := newMockDatabase()
+	remote := NewTableInDatabase("db2", "table1")
+	remoteField := NewNumberField(remote, "field1")
+
+	_, _ = db.Select(field2, remoteField).From(Table1, remote).Where(field1.Equals(remoteField)).FetchAll()
+	assertLastSql(t, "SELECT `table1`.`field2`, `db2`.`table1`.`field1` FROM `table1`, `db2`.`table1` "+
+		"WHERE `table1`.`field1` = `db2`.`table1`.`field1`")
+
+	_, _ = db.SelectFrom(remote).Where(remoteField.Equals(1)).FetchAll()
+	assertLastSql(t, "SELECT * FROM `db2`.`table1` WHERE `field1` = 1")
+
+	db.(*database).dialect = dialectPostgres
+	_, _ = db.SelectFrom(remote).Where(remoteField.Equals(1)).FetchAll()
+	assertLastSql(t, `SELECT * FROM "db2"."table1" WHERE "field1" = 1`)
+}
+
+func TestAllFieldsExcept(t *testing.T) {
+	db := newMockDatabase()
+	_, _ = db.Select(AllFieldsExcept(fixture, fixture.Data, fixture.Nickname)).From(fixture).FetchAll()
+	assertLastSql(t, "SELECT `id`, `name`, `created_at` FROM `fixture`")
+
+	// a field of another table with the same name is not excluded
+	other := NewStringField(NewTable("other"), "data")
+	if fields := AllFieldsExcept(fixture, other); len(fields) != 5 {
+		t.Error(fields)
+	}
+	if fields := AllFieldsExcept(fixture); len(fields) != 5 {
+		t.Error(fields)
+	}
+}
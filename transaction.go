@@ -3,6 +3,7 @@ package sqlingo
 import (
 	"context"
 	"database/sql"
+	"fmt"
 )
 
 // Transaction is the interface of a transaction with underlying sql.Tx object.
@@ -11,14 +12,21 @@ type Transaction interface {
 	GetDB() *sql.DB
 	GetTx() *sql.Tx
 	Query(sql string) (Cursor, error)
+	QueryContext(ctx context.Context, sql string) (Cursor, error)
 	Execute(sql string) (sql.Result, error)
+	ExecuteContext(ctx context.Context, sql string) (sql.Result, error)
 
 	Select(fields ...interface{}) selectWithFields
 	SelectDistinct(fields ...interface{}) selectWithFields
 	SelectFrom(tables ...Table) selectWithTables
 	InsertInto(table Table) insertWithTable
 	Update(table Table) updateWithSet
+	Sync(table Table, models interface{}, keyFields ...Field) syncWithModels
 	DeleteFrom(table Table) deleteWithTable
+	CreateTable(model Model) createTableWithModel
+	CreateTempTable(name string, query toSelectFinal) createTempTableWithQuery
+	DropTable(table Table) dropTableWithTable
+	AlterTable(table Table) alterTableWithTable
 }
 
 func (d *database) GetTx() *sql.Tx {
@@ -56,3 +64,28 @@ func (d *database) BeginTx(ctx context.Context, opts *sql.TxOptions, f func(tx T
 	isCommitted = true
 	return nil
 }
+
+// Transaction executes fn with a database bound to a new transaction, which is committed if fn returns nil,
+// and rolled back if fn returns an error or panics, with the error or panic wrapped in the returned error.
+// Called on a database already in a transaction, e.g. from fn, it reuses the transaction instead of opening
+// a new one, so that the outermost call decides whether to commit.
+func (d *database) Transaction(ctx context.Context, fn func(tx Database) error) error {
+	if d.tx != nil {
+		return fn(d)
+	}
+	return d.BeginTx(ctx, nil, func(tx Transaction) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				if panicErr, ok := r.(error); ok {
+					err = fmt.Errorf("transaction rolled back on panic: %w", panicErr)
+				} else {
+					err = fmt.Errorf("transaction rolled back on panic: %v", r)
+				}
+			}
+		}()
+		if err := fn(tx.(*database)); err != nil {
+			return fmt.Errorf("transaction rolled back: %w", err)
+		}
+		return nil
+	})
+}
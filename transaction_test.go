@@ -81,3 +81,89 @@ func TestTransaction(t *testing.T) {
 		t.Error("should get error here")
 	}
 }
+
+func TestTransactionContext(t *testing.T) {
+	db := newMockDatabase()
+	err := db.BeginTx(context.Background(), nil, func(tx Transaction) error {
+		if _, err := tx.ExecuteContext(context.Background(), "<dummy>"); err != nil {
+			t.Error(err)
+		}
+		cursor, err := tx.QueryContext(context.Background(), "<dummy>")
+		if err != nil {
+			t.Error(err)
+		} else {
+			_ = cursor.Close()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := db.ExecuteContext(ctx, "<dummy>"); !errors.Is(err, context.Canceled) {
+		t.Error(err)
+	}
+	if _, err := db.QueryContext(ctx, "<dummy>"); !errors.Is(err, context.Canceled) {
+		t.Error(err)
+	}
+	if _, err := db.SelectFrom(table1).WithContext(ctx).FetchAll(); !errors.Is(err, context.Canceled) {
+		t.Error(err)
+	}
+	if _, err := db.Update(table1).Set(field1, 1).Where(True()).WithContext(ctx).Execute(); !errors.Is(err, context.Canceled) {
+		t.Error(err)
+	}
+}
+
+func TestTransactionHelper(t *testing.T) {
+	db := newMockDatabase()
+	err := db.Transaction(context.Background(), func(tx Database) error {
+		if tx.(*database).tx == nil {
+			t.Error("should be in a transaction")
+		}
+		// the nested call joins the transaction
+		return tx.Transaction(context.Background(), func(nested Database) error {
+			if nested != tx {
+				t.Error("should reuse the transaction")
+			}
+			_, err := nested.Execute("<dummy>")
+			return err
+		})
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	if !sharedMockConn.mockTx.isCommitted || sharedMockConn.mockTx.isRolledBack {
+		t.Error(sharedMockConn.mockTx)
+	}
+
+	cause := errors.New("error")
+	err = db.Transaction(context.Background(), func(tx Database) error {
+		return tx.Transaction(context.Background(), func(nested Database) error {
+			return cause
+		})
+	})
+	if !errors.Is(err, cause) {
+		t.Error(err)
+	}
+	if sharedMockConn.mockTx.isCommitted || !sharedMockConn.mockTx.isRolledBack {
+		t.Error(sharedMockConn.mockTx)
+	}
+
+	err = db.Transaction(context.Background(), func(tx Database) error {
+		panic(cause)
+	})
+	if !errors.Is(err, cause) {
+		t.Error(err)
+	}
+	if sharedMockConn.mockTx.isCommitted || !sharedMockConn.mockTx.isRolledBack {
+		t.Error(sharedMockConn.mockTx)
+	}
+
+	if err := db.Transaction(context.Background(), func(tx Database) error {
+		panic("boom")
+	}); err == nil {
+		t.Error("should get error here")
+	}
+}
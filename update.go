@@ -3,6 +3,10 @@ package sqlingo
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -13,6 +17,8 @@ type updateStatus struct {
 	where       BooleanExpression
 	orderBys    []OrderBy
 	limit       *int
+	returning   []Field
+	routeHint   string
 	ctx         context.Context
 }
 
@@ -23,7 +29,9 @@ func (d *database) Update(table Table) updateWithSet {
 type updateWithSet interface {
 	Set(Field Field, value interface{}) updateWithSet
 	SetIf(prerequisite bool, Field Field, value interface{}) updateWithSet
+	SetCase(field Field, keyField Field, values interface{}) updateWithSet
 	Where(conditions ...BooleanExpression) updateWithWhere
+	WhereRaw(sql string, args ...interface{}) updateWithWhere
 	OrderBy(orderBys ...OrderBy) updateWithOrder
 	Limit(limit int) updateWithLimit
 }
@@ -31,6 +39,7 @@ type updateWithSet interface {
 type updateWithWhere interface {
 	toUpdateWithContext
 	toUpdateFinal
+	Returning(fields ...Field) toReturningWithContext
 	OrderBy(orderBys ...OrderBy) updateWithOrder
 	Limit(limit int) updateWithLimit
 }
@@ -38,12 +47,14 @@ type updateWithWhere interface {
 type updateWithOrder interface {
 	toUpdateWithContext
 	toUpdateFinal
+	Returning(fields ...Field) toReturningWithContext
 	Limit(limit int) updateWithLimit
 }
 
 type updateWithLimit interface {
 	toUpdateWithContext
 	toUpdateFinal
+	Returning(fields ...Field) toReturningWithContext
 }
 
 type toUpdateWithContext interface {
@@ -51,8 +62,11 @@ type toUpdateWithContext interface {
 }
 
 type toUpdateFinal interface {
+	RouteHint(hint string) toUpdateFinal
 	GetSQL() (string, error)
+	GetSQLWithArgs() (string, []interface{}, error)
 	Execute() (sql.Result, error)
+	ReturningModels(dest interface{}) (rows int, err error)
 }
 
 func (s updateStatus) Set(field Field, value interface{}) updateWithSet {
@@ -71,11 +85,76 @@ func (s updateStatus) SetIf(prerequisite bool, field Field, value interface{}) u
 	return s
 }
 
+// SetCase sets the field to a different value for each row, chosen by the value of keyField from values,
+// which must be a map, e.g. SET val = CASE id WHEN 1 THEN 'a' WHEN 2 THEN 'b' ELSE val END.
+// The rows whose keys are not in the map keep their values.
+func (s updateStatus) SetCase(field Field, keyField Field, values interface{}) updateWithSet {
+	return s.Set(field, expression{builder: func(scope scope) (string, error) {
+		mapValue := reflect.ValueOf(values)
+		if mapValue.Kind() != reflect.Map {
+			return "", errors.New("values of SetCase should be a map")
+		}
+		fieldSql, err := field.GetSQL(scope)
+		if err != nil {
+			return "", err
+		}
+		if mapValue.Len() == 0 {
+			return fieldSql, nil
+		}
+		keyFieldSql, err := keyField.GetSQL(scope)
+		if err != nil {
+			return "", err
+		}
+
+		keys := mapValue.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return lessValue(keys[i], keys[j])
+		})
+
+		var sb strings.Builder
+		sb.WriteString("CASE " + keyFieldSql)
+		for _, key := range keys {
+			keySql, _, err := getSQL(scope, key.Interface())
+			if err != nil {
+				return "", err
+			}
+			valueSql, _, err := getSQL(scope, mapValue.MapIndex(key).Interface())
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(" WHEN " + keySql + " THEN " + valueSql)
+		}
+		sb.WriteString(" ELSE " + fieldSql + " END")
+		return sb.String(), nil
+	}})
+}
+
+// lessValue orders map keys so that the generated SQL is stable.
+func lessValue(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return a.Uint() < b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() < b.Float()
+	case reflect.String:
+		return a.String() < b.String()
+	default:
+		return fmt.Sprint(a.Interface()) < fmt.Sprint(b.Interface())
+	}
+}
+
 func (s updateStatus) Where(conditions ...BooleanExpression) updateWithWhere {
 	s.where = And(conditions...)
 	return s
 }
 
+// WhereRaw sets the condition in raw SQL, where the ? placeholders are replaced by the quoted args.
+func (s updateStatus) WhereRaw(sql string, args ...interface{}) updateWithWhere {
+	return s.Where(Raw(sql, args...))
+}
+
 func (s updateStatus) OrderBy(orderBys ...OrderBy) updateWithOrder {
 	s.orderBys = orderBys
 	return s
@@ -86,12 +165,33 @@ func (s updateStatus) Limit(limit int) updateWithLimit {
 	return s
 }
 
+// RouteHint prepends the hint as a comment to the statement for a sharding proxy, e.g. /* shard=users_3 */.
+func (s updateStatus) RouteHint(hint string) toUpdateFinal {
+	s.routeHint = hint
+	return s
+}
+
 func (s updateStatus) GetSQL() (string, error) {
+	return s.scope.Database.finishSQL(s.buildSQL(nil))
+}
+
+// GetSQLWithArgs returns the SQL with placeholders and the values bound to them.
+func (s updateStatus) GetSQLWithArgs() (string, []interface{}, error) {
+	args := &argList{}
+	sqlString, err := s.scope.Database.finishSQL(s.buildSQL(args))
+	return sqlString, args.values, err
+}
+
+func (s updateStatus) buildSQL(args *argList) (string, error) {
+	s.scope.args = args
 	if len(s.assignments) == 0 {
 		return "/* UPDATE without SET clause */ DO 0", nil
 	}
 	var sb strings.Builder
 	sb.Grow(128)
+	if err := appendRouteHint(&sb, s.routeHint); err != nil {
+		return "", err
+	}
 
 	sb.WriteString("UPDATE ")
 	sb.WriteString(s.scope.Tables[0].GetSQL(s.scope))
@@ -121,18 +221,135 @@ func (s updateStatus) GetSQL() (string, error) {
 		sb.WriteString(strconv.Itoa(*s.limit))
 	}
 
+	if err := appendReturning(&sb, s.scope, s.returning); err != nil {
+		return "", err
+	}
+
 	return sb.String(), nil
 }
 
+// Returning appends a RETURNING clause, and the statement is executed as a query to fetch the returned rows.
+// It is supported by PostgreSQL and SQLite.
+func (s updateStatus) Returning(fields ...Field) toReturningWithContext {
+	s.returning = fields
+	return returningStatus{database: s.scope.Database, statement: s}
+}
+
 func (s updateStatus) WithContext(ctx context.Context) toUpdateFinal {
 	s.ctx = ctx
 	return s
 }
 
 func (s updateStatus) Execute() (sql.Result, error) {
-	sqlString, err := s.GetSQL()
+	return s.scope.Database.executeStatement(s.ctx, s)
+}
+
+// ReturningModels executes the update and scans the updated rows into dest, which should be a pointer to a slice
+// of models. On PostgreSQL and SQLite the rows are returned by RETURNING. On MySQL, the keys of the matched rows
+// are captured by SELECT ... FOR UPDATE, the rows of the keys are updated, and they are selected again after
+// the update in the same transaction.
+// The rows are identified by the primary key of the table, which should not be changed by the update.
+func (s updateStatus) ReturningModels(dest interface{}) (rows int, err error) {
+	fields := s.scope.Tables[0].GetFields()
+	if len(fields) == 0 {
+		return 0, errors.New("table has no fields")
+	}
+	if getDialect(s.scope) != dialectMySQL {
+		return s.Returning(fields...).WithContext(s.ctx).FetchAll(dest)
+	}
+
+	table, ok := s.scope.Tables[0].(PrimaryKeyTable)
+	if !ok || len(table.GetPrimaryKeyFields()) == 0 {
+		return 0, errors.New("ReturningModels requires a table with a primary key on MySQL")
+	}
+	keyFields := table.GetPrimaryKeyFields()
+	for _, assignment := range s.assignments {
+		for _, keyField := range keyFields {
+			if isSameField(assignment.field, keyField) {
+				return 0, errors.New("ReturningModels cannot select the rows again after their primary key is set on MySQL")
+			}
+		}
+	}
+	return s.reselectModels(keyFields, dest)
+}
+
+// isSameField reports whether a and b are the same column of the same table.
+func isSameField(a, b Field) bool {
+	if a.GetTable() == nil || b.GetTable() == nil || a.GetTable().GetName() != b.GetTable().GetName() {
+		return false
+	}
+	aName, aErr := getFieldName(a)
+	bName, bErr := getFieldName(b)
+	return aErr == nil && bErr == nil && aName == bName
+}
+
+func (s updateStatus) reselectModels(keyFields []Field, dest interface{}) (rows int, err error) {
+	db := s.scope.Database
+	if db.tx == nil {
+		err = db.BeginTx(s.ctx, nil, func(tx Transaction) error {
+			s.scope.Database = tx.(*database)
+			rows, err = s.reselectModels(keyFields, dest)
+			return err
+		})
+		return
+	}
+
+	table := s.scope.Tables[0]
+	keySelect := db.Select(keyFields).From(table).(selectStatus)
+	keySelect.base.where = s.where
+	keySelect.orderBys = s.orderBys
+	keySelect.limit = s.limit
+	keySelect.lock = " FOR UPDATE"
+	keySelect.ctx = s.ctx
+	// the keys are of the rows to update, which are not bounded by SetMaxFetchRows
+	cursor, err := keySelect.queryCursor()
 	if err != nil {
-		return nil, err
+		return
+	}
+	var conditions []BooleanExpression
+	var singleKeys []interface{}
+	for cursor.Next() {
+		keys := make([]*scannedValue, len(keyFields))
+		pointers := make([]interface{}, len(keyFields))
+		for i, keyField := range keyFields {
+			keys[i] = newScannedValue(keyField)
+			pointers[i] = keys[i]
+		}
+		if err = cursor.Scan(pointers...); err != nil {
+			_ = cursor.Close()
+			return
+		}
+		if len(keyFields) == 1 {
+			singleKeys = append(singleKeys, keys[0].value)
+			continue
+		}
+		equalities := make([]BooleanExpression, len(keyFields))
+		for i, keyField := range keyFields {
+			equalities[i] = keyField.Equals(keys[i].value)
+		}
+		conditions = append(conditions, And(equalities...))
+	}
+	if err = cursor.Close(); err != nil {
+		return
+	}
+	if len(singleKeys) > 0 {
+		conditions = []BooleanExpression{keyFields[0].In(singleKeys...)}
+	}
+	if len(conditions) == 0 {
+		return 0, nil
+	}
+
+	// the rows locked above are updated by their keys, rather than the conditions that may match other rows by now
+	s.where = Or(conditions...)
+	s.orderBys = nil
+	s.limit = nil
+	if _, err = s.Execute(); err != nil {
+		return
+	}
+	reselect := db.SelectFrom(table).Where(Or(conditions...)).(selectStatus)
+	reselect.ctx = s.ctx
+	if cursor, err = reselect.queryCursor(); err != nil {
+		return
 	}
-	return s.scope.Database.ExecuteContext(s.ctx, sqlString)
+	return fetchAll(cursor, dest)
 }
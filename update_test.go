@@ -71,3 +71,40 @@ func TestUpdate(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestUpdateSetCase(t *testing.T) {
+	db := newMockDatabase()
+
+	_, _ = db.Update(Table1).
+		SetCase(field2, field1, map[int]string{2: "b", 1: "a", 10: "c"}).
+		Where(field1.In(1, 2, 10)).
+		Execute()
+	assertLastSql(t, "UPDATE `table1` SET `field2` = CASE `field1` WHEN 1 THEN 'a' WHEN 2 THEN 'b' WHEN 10 THEN 'c' ELSE `field2` END "+
+		"WHERE `field1` IN (1, 2, 10)")
+
+	_, _ = db.Update(Table1).SetCase(field2, field1, map[string]int{}).Where(True()).Execute()
+	assertLastSql(t, "UPDATE `table1` SET `field2` = `field2`")
+
+	if _, err := db.Update(Table1).SetCase(field2, field1, []int{1}).Where(True()).Execute(); err == nil {
+		t.Error("should fail on non-map values")
+	}
+}
+
+func TestUpdateWhereCorrelatedExists(t *testing.T) {
+	db := newMockDatabase()
+
+	// the columns of the updated table are qualified inside the subquery, as it is not in the scope of the subquery
+	_, _ = db.Update(table1).
+		Set(field1, 2).
+		Where(field1.Equals(1), Exists(db.Select(1).From(table2).Where(field3.Equals(field2), field3.GreaterThan(0)))).
+		Execute()
+	assertLastSql(t, "UPDATE `table1` SET `field1` = 2 WHERE `field1` = 1 AND "+
+		"EXISTS (SELECT 1 FROM `table2` WHERE `field3` = `table1`.`field2` AND `field3` > 0)")
+
+	db.(*database).dialect = dialectPostgres
+	_, _ = db.Update(Test).
+		Set(Test.F1, 2).
+		Where(NotExists(db.Select(1).From(table2).Where(field3.Equals(Test.F1)))).
+		Execute()
+	assertLastSql(t, `UPDATE "test" SET "f1" = 2 WHERE NOT EXISTS (SELECT 1 FROM "table2" WHERE "field3" = "test"."f1")`)
+}
@@ -32,3 +32,19 @@ func assertError(t *testing.T, value interface{}) {
 		t.Errorf("value [%v] generated [%s] expected error", value, generatedSql)
 	}
 }
+
+func assertDialectValue(t *testing.T, dialect dialect, value interface{}, expectedSql string) {
+	t.Helper()
+	scope := scope{Database: &database{dialect: dialect}}
+	if generatedSql, _, _ := getSQL(scope, value); generatedSql != expectedSql {
+		t.Errorf("value [%v] generated [%s] expected [%s]", value, generatedSql, expectedSql)
+	}
+}
+
+func assertDialectError(t *testing.T, dialect dialect, value interface{}) {
+	t.Helper()
+	scope := scope{Database: &database{dialect: dialect}}
+	if generatedSql, _, err := getSQL(scope, value); err == nil {
+		t.Errorf("value [%v] generated [%s] expected error", value, generatedSql)
+	}
+}
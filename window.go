@@ -0,0 +1,111 @@
+package sqlingo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RowNumber creates an expression of ROW_NUMBER() window function, which should be followed by Over.
+func RowNumber() NumberExpression {
+	return staticExpression("ROW_NUMBER()", 0, false)
+}
+
+// Rank creates an expression of RANK() window function, which should be followed by Over.
+func Rank() NumberExpression {
+	return staticExpression("RANK()", 0, false)
+}
+
+// DenseRank creates an expression of DENSE_RANK() window function, which should be followed by Over.
+func DenseRank() NumberExpression {
+	return staticExpression("DENSE_RANK()", 0, false)
+}
+
+// Lag creates an expression of LAG(expr, offset) window function, which should be followed by Over.
+func Lag(expr interface{}, offset int) UnknownExpression {
+	return function("LAG", expr, offset)
+}
+
+// Lead creates an expression of LEAD(expr, offset) window function, which should be followed by Over.
+func Lead(expr interface{}, offset int) UnknownExpression {
+	return function("LEAD", expr, offset)
+}
+
+// Over creates an expression of the window function or aggregator over a window,
+// e.g. ROW_NUMBER() OVER (PARTITION BY dept ORDER BY salary DESC).
+// PARTITION BY and ORDER BY are omitted when empty.
+//
+// A distinct aggregator, e.g. field.Distinct().Count(), is rendered over a window only for databases of
+// unknown dialect, since MySQL, SQLite, PostgreSQL and SQL Server all reject it. Use CountDistinctOver
+// for the distinct count of a whole partition instead.
+func (e expression) Over(partitionBy []Expression, orderBy []OrderBy) UnknownExpression {
+	return e.over(partitionBy, orderBy, "")
+}
+
+// RunningSum creates an expression of the cumulative sum of expr in the order,
+// i.e. SUM(expr) OVER (ORDER BY orderBy ROWS UNBOUNDED PRECEDING).
+func RunningSum(expr interface{}, orderBy OrderBy) NumberExpression {
+	return function("SUM", expr).over(nil, []OrderBy{orderBy}, "ROWS UNBOUNDED PRECEDING")
+}
+
+// MovingAverage creates an expression of the average of expr over the current row and the window-1 rows
+// before it in the order, i.e. AVG(expr) OVER (ORDER BY orderBy ROWS BETWEEN window-1 PRECEDING AND CURRENT ROW).
+func MovingAverage(expr interface{}, orderBy OrderBy, window int) NumberExpression {
+	frame := "ROWS BETWEEN " + strconv.Itoa(window-1) + " PRECEDING AND CURRENT ROW"
+	return function("AVG", expr).over(nil, []OrderBy{orderBy}, frame)
+}
+
+// CountDistinctOver creates an expression of the number of distinct non-null values of expr in the partition,
+// i.e. COUNT(DISTINCT expr) OVER (PARTITION BY partitionBy), by adding up the dense ranks in both orders
+// as all the supported dialects lack distinct aggregators over a window.
+// It counts the whole partition, so it cannot be used for a running distinct count.
+func CountDistinctOver(expr Expression, partitionBy []Expression) NumberExpression {
+	ascending := DenseRank().Over(partitionBy, []OrderBy{expr})
+	descending := DenseRank().Over(partitionBy, []OrderBy{expr.Desc()})
+	// NULL is ranked as a value
+	hasNull := function("MAX", Case().WhenThen(expr.IsNull(), 1).Else(0).End()).over(partitionBy, nil, "")
+	return ascending.Add(descending).Sub(1).Sub(hasNull)
+}
+
+// over creates an expression over the window, where the frame clause follows ORDER BY if not empty.
+func (e expression) over(partitionBy []Expression, orderBy []OrderBy, frame string) expression {
+	return expression{builder: func(scope scope) (string, error) {
+		if dialect := getDialect(scope); e.isDistinctAggregate && dialect != dialectUnknown {
+			return "", fmt.Errorf("DISTINCT aggregators over a window are not supported by %s, "+
+				"use CountDistinctOver for the distinct count of a partition", dialect)
+		}
+		sql, err := e.GetSQL(scope)
+		if err != nil {
+			return "", err
+		}
+
+		var sb strings.Builder
+		sb.WriteString(sql)
+		sb.WriteString(" OVER (")
+		if len(partitionBy) > 0 {
+			partitionBySql, err := commaExpressions(scope, partitionBy)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString("PARTITION BY ")
+			sb.WriteString(partitionBySql)
+		}
+		if len(orderBy) > 0 {
+			orderBySql, err := commaOrderBys(scope, orderBy)
+			if err != nil {
+				return "", err
+			}
+			if len(partitionBy) > 0 {
+				sb.WriteString(" ")
+			}
+			sb.WriteString("ORDER BY ")
+			sb.WriteString(orderBySql)
+		}
+		if frame != "" {
+			sb.WriteString(" ")
+			sb.WriteString(frame)
+		}
+		sb.WriteString(")")
+		return sb.String(), nil
+	}}
+}
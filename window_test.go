@@ -0,0 +1,53 @@
+package sqlingo
+
+import "testing"
+
+func TestWindowFunctions(t *testing.T) {
+	a := expression{sql: "a"}
+	b := expression{sql: "b"}
+
+	assertValue(t, RowNumber().Over(nil, nil), "ROW_NUMBER() OVER ()")
+	assertValue(t, RowNumber().Over([]Expression{a}, []OrderBy{b.Desc()}), "ROW_NUMBER() OVER (PARTITION BY a ORDER BY b DESC)")
+	assertValue(t, Rank().Over(nil, []OrderBy{a, b}), "RANK() OVER (ORDER BY a, b)")
+	assertValue(t, DenseRank().Over([]Expression{a, b}, nil), "DENSE_RANK() OVER (PARTITION BY a, b)")
+	assertValue(t, Lag(a, 1).Over(nil, []OrderBy{b}), "LAG(a, 1) OVER (ORDER BY b)")
+	assertValue(t, Lead(a, 2).Over([]Expression{b}, []OrderBy{a}), "LEAD(a, 2) OVER (PARTITION BY b ORDER BY a)")
+	assertValue(t, Sum(a).Over([]Expression{b}, nil).Add(1), "SUM(a) OVER (PARTITION BY b) + 1")
+
+	db := newMockDatabase()
+	_, _ = db.Select(field1, RowNumber().Over([]Expression{field1}, []OrderBy{field2.Desc()}).As("rn")).From(table1).FetchAll()
+	assertLastSql(t, "SELECT `field1`, ROW_NUMBER() OVER (PARTITION BY `field1` ORDER BY `field2` DESC) AS rn FROM `table1`")
+}
+
+func TestRunningAggregates(t *testing.T) {
+	a := expression{sql: "a"}
+	d := expression{sql: "d"}
+
+	assertValue(t, RunningSum(a, d), "SUM(a) OVER (ORDER BY d ROWS UNBOUNDED PRECEDING)")
+	assertValue(t, MovingAverage(a, d.Desc(), 7), "AVG(a) OVER (ORDER BY d DESC ROWS BETWEEN 6 PRECEDING AND CURRENT ROW)")
+	assertValue(t, RunningSum(a, d).Div(2), "SUM(a) OVER (ORDER BY d ROWS UNBOUNDED PRECEDING) / 2")
+
+	db := newMockDatabase()
+	_, _ = db.Select(field1, RunningSum(field2, field1).As("total")).From(table1).FetchAll()
+	assertLastSql(t, "SELECT `field1`, SUM(`field2`) OVER (ORDER BY `field1` ROWS UNBOUNDED PRECEDING) AS total FROM `table1`")
+}
+
+func TestDistinctOverWindow(t *testing.T) {
+	a := expression{sql: "a"}
+	b := expression{sql: "b"}
+
+	assertValue(t, a.Distinct().Count(), "COUNT(DISTINCT a)")
+	assertValue(t, Sum(a.Distinct()), "SUM(DISTINCT a)")
+	assertError(t, a.Distinct().Count().Over([]Expression{b}, nil))
+	assertError(t, a.CountDistinct().Over(nil, nil))
+	assertError(t, RunningSum(a.Distinct(), b))
+
+	sql, _, err := getSQL(scope{}, a.Distinct().Count().Over([]Expression{b}, nil))
+	if err != nil || sql != "COUNT(DISTINCT a) OVER (PARTITION BY b)" {
+		t.Error(sql, err)
+	}
+
+	assertValue(t, CountDistinctOver(a, []Expression{b}),
+		"DENSE_RANK() OVER (PARTITION BY b ORDER BY a) + DENSE_RANK() OVER (PARTITION BY b ORDER BY a DESC) - 1"+
+			" - MAX(CASE WHEN a IS NULL THEN 1 ELSE 0 END) OVER (PARTITION BY b)")
+}